@@ -0,0 +1,153 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Forever is a TTL for responses that never expire, such as finished submissions.
+const Forever time.Duration = -1
+
+// Cache stores raw response bodies of read-only endpoints.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, data []byte, ttl time.Duration) error
+}
+
+// CacheTTL holds how long each kind of cached response stays fresh. A zero
+// value disables caching for that endpoint.
+type CacheTTL struct {
+	Problem   time.Duration
+	Languages time.Duration
+	Statement time.Duration
+}
+
+var DefaultCacheTTL = CacheTTL{
+	Problem:   24 * time.Hour,
+	Languages: 7 * 24 * time.Hour,
+	Statement: 7 * 24 * time.Hour,
+}
+
+// DiskCache keeps one JSON file per entry in Dir.
+type DiskCache struct {
+	Dir string
+}
+
+type cacheEntry struct {
+	Expires time.Time `json:"expires,omitempty"`
+	Data    []byte    `json:"data"`
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	raw, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, false
+	}
+	if !entry.Expires.IsZero() && time.Now().After(entry.Expires) {
+		_ = os.Remove(d.path(key))
+		return nil, false
+	}
+	return entry.Data, true
+}
+
+func (d *DiskCache) Put(key string, data []byte, ttl time.Duration) error {
+	entry := cacheEntry{Data: data}
+	if ttl != Forever {
+		entry.Expires = time.Now().Add(ttl)
+	}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.Dir, 0700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so concurrent readers never see half an entry.
+	tmp, err := os.CreateTemp(d.Dir, "entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+// cacheKey identifies a response by URL and by who asked for it, since the
+// same endpoint can return different data to different users. Only a hash of
+// the token is used.
+func (c *Client) cacheKey(path string) string {
+	identity := "guest"
+	if token, err := c.token(); err == nil && token != "" {
+		sum := sha256.Sum256([]byte(token))
+		identity = hex.EncodeToString(sum[:8])
+	}
+	return http.MethodGet + " " + c.URL(path) + " " + identity
+}
+
+type noCacheKey struct{}
+
+// WithoutCache returns a context whose requests bypass the response cache,
+// for bulk downloads such as a database refresh whose responses are stored
+// elsewhere and never read from the cache again.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// getCached is get with a response cache. keep, when given, decides after
+// decoding whether the response may be stored.
+func (c *Client) getCached(ctx context.Context, path string, reqType RequestType, ttl time.Duration, out any, keep func() bool) error {
+	if c.Cache == nil || ttl == 0 || ctx.Value(noCacheKey{}) != nil {
+		return c.get(ctx, path, reqType, out)
+	}
+
+	key := c.cacheKey(path)
+	if !c.RefreshCache {
+		if data, ok := c.Cache.Get(key); ok && decode(data, out) == nil {
+			return nil
+		}
+	}
+
+	data, err := c.Do(ctx, http.MethodGet, path, nil, reqType)
+	if err != nil {
+		return err
+	}
+	if err := decode(data, out); err != nil {
+		return err
+	}
+
+	if keep == nil || keep() {
+		// A failed write only costs a refetch next time.
+		_ = c.Cache.Put(key, data, ttl)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		wantHit  bool
+		wantFile bool
+	}{
+		{"fresh", time.Hour, true, true},
+		{"forever", Forever, true, true},
+		{"expired", time.Nanosecond, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &DiskCache{Dir: t.TempDir()}
+			if err := cache.Put("key", []byte("data"), tt.ttl); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(time.Millisecond)
+
+			data, ok := cache.Get("key")
+			if ok != tt.wantHit {
+				t.Fatalf("hit = %v, want %v", ok, tt.wantHit)
+			}
+			if ok && string(data) != "data" {
+				t.Errorf("data = %q, want %q", data, "data")
+			}
+
+			// Expired entries are removed when read.
+			if _, err := os.Stat(cache.path("key")); (err == nil) != tt.wantFile {
+				t.Errorf("entry file exists = %v, want %v", err == nil, tt.wantFile)
+			}
+		})
+	}
+
+	cache := &DiskCache{Dir: t.TempDir()}
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("hit for a key never stored")
+	}
+}
+
+func TestGetCached(t *testing.T) {
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		keep      func() bool
+		refresh   bool
+		noCache   bool
+		wantCalls int32
+	}{
+		{"cached", time.Hour, nil, false, false, 1},
+		{"ttl zero disables caching", 0, nil, false, false, 2},
+		{"keep rejects the response", time.Hour, func() bool { return false }, false, false, 2},
+		{"refresh skips entries", time.Hour, nil, true, false, 2},
+		{"WithoutCache bypasses it", time.Hour, nil, false, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"status": "success", "data": %d}`, calls.Add(1))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL+"/", nil)
+			client.Cache = &DiskCache{Dir: t.TempDir()}
+			client.RefreshCache = tt.refresh
+
+			ctx := context.Background()
+			if tt.noCache {
+				ctx = WithoutCache(ctx)
+			}
+
+			var first, second int
+			if err := client.getCached(ctx, "problem", RequestNone, tt.ttl, &first, tt.keep); err != nil {
+				t.Fatal(err)
+			}
+			if err := client.getCached(ctx, "problem", RequestNone, tt.ttl, &second, tt.keep); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("requests = %d, want %d", got, tt.wantCalls)
+			}
+			if int32(second) != tt.wantCalls {
+				t.Errorf("second response = %d, want %d", second, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCacheKeyDependsOnToken(t *testing.T) {
+	guest := NewClient("http://example.org/", nil)
+	alice := NewClient("http://example.org/", TokenFunc(func() (string, error) { return "alice", nil }))
+	bob := NewClient("http://example.org/", TokenFunc(func() (string, error) { return "bob", nil }))
+	broken := NewClient("http://example.org/", TokenFunc(func() (string, error) { return "", fmt.Errorf("broken") }))
+
+	keys := map[string]string{}
+	for name, client := range map[string]*Client{"guest": guest, "alice": alice, "bob": bob} {
+		key := client.cacheKey("problem")
+		if other, ok := keys[key]; ok {
+			t.Errorf("%s and %s share the cache key %q", name, other, key)
+		}
+		keys[key] = name
+	}
+
+	if broken.cacheKey("problem") != guest.cacheKey("problem") {
+		t.Error("a token that can't be read should be cached as a guest")
+	}
+	if key := alice.cacheKey("problem"); strings.Contains(key, "alice") {
+		t.Errorf("cache key %q contains the token", key)
+	}
+}
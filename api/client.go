@@ -0,0 +1,297 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+// Package api is a typed client for the Kilonova HTTP API. Every method takes a
+// context and reports failures through returned errors, so it can be embedded in
+// other tools as well as used by the kncli commands.
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type RequestType int
+
+const (
+	RequestNone RequestType = iota
+	RequestFormAuth
+	RequestJSON
+	RequestFormGuest
+	RequestDownloadZip
+	RequestInfo
+	RequestMultipartForm
+	RequestJSONAuth
+)
+
+const statusSuccess = "success"
+
+var ErrNotAuthenticated = errors.New("you must be authenticated to do this")
+
+// TokenSource supplies the session token sent with each request, "" when
+// signed out. An error means a stored token can't be used: requests that need
+// authentication fail with it and the others are sent as a guest.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+type TokenFunc func() (string, error)
+
+func (f TokenFunc) Token() (string, error) {
+	return f()
+}
+
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Tokens     TokenSource
+	UserAgent  string
+	Retry      RetryPolicy
+	Limiter    *RateLimiter
+
+	// Cache, when set, stores responses of read-only endpoints for CacheTTL.
+	// RefreshCache skips cached entries but still stores fresh responses.
+	Cache        Cache
+	CacheTTL     CacheTTL
+	RefreshCache bool
+
+	// BeforeAuth, when set, runs before each request that needs the session,
+	// e.g. to extend it when it is about to expire.
+	BeforeAuth func()
+}
+
+func NewClient(baseURL string, tokens TokenSource) *Client {
+	// The default options have no proxy or CA file, so they can't fail.
+	httpClient, _ := NewHTTPClient(DefaultHTTPOptions)
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: httpClient,
+		Tokens:     tokens,
+	}
+}
+
+// NormalizeBaseURL accepts either the site root or its /api/ path and returns
+// the site root with a trailing slash.
+func NormalizeBaseURL(raw string) (string, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid API URL %q: %w", raw, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return "", fmt.Errorf("invalid API URL %q: must be an absolute http(s) URL", raw)
+	}
+
+	parsed.Path = strings.TrimSuffix(strings.TrimSuffix(parsed.Path, "/"), "/"+strings.TrimSuffix(API_URL, "/")) + "/"
+	parsed.RawQuery, parsed.Fragment = "", ""
+
+	return parsed.String(), nil
+}
+
+// URL resolves an endpoint path (optionally a format string) against BaseURL.
+// Absolute URLs are returned unchanged.
+func (c *Client) URL(path string, args ...any) string {
+	if len(args) > 0 {
+		path = fmt.Sprintf(path, args...)
+	}
+	if strings.Contains(path, "://") {
+		return path
+	}
+
+	base := c.BaseURL
+	if base == "" {
+		base = DEFAULT_BASE_URL
+	}
+	return base + path
+}
+
+func (c *Client) token() (string, error) {
+	if c.Tokens == nil {
+		return "", nil
+	}
+	return c.Tokens.Token()
+}
+
+// needsAuth reports whether requests of this type fail without a session.
+func (t RequestType) needsAuth() bool {
+	return t == RequestFormAuth || t == RequestJSONAuth || t == RequestDownloadZip || t == RequestMultipartForm
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+func (c *Client) prepare(req *http.Request, reqType RequestType, contentType ...string) error {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	token, err := c.token()
+	if reqType.needsAuth() {
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			return ErrNotAuthenticated
+		}
+		if c.BeforeAuth != nil {
+			c.BeforeAuth()
+		}
+	}
+	hasToken := token != "" && err == nil
+
+	switch reqType {
+	case RequestFormAuth, RequestFormGuest:
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	case RequestJSON, RequestJSONAuth:
+		req.Header.Set("Content-Type", "application/json")
+	case RequestDownloadZip, RequestInfo:
+		req.Header.Set("Content-Type", "application/zip")
+		req.Header.Set("Accept", "application/zip")
+		if hasToken {
+			req.AddCookie(&http.Cookie{Name: "kn-sessionid", Value: token})
+		}
+	case RequestMultipartForm:
+		if len(contentType) == 0 {
+			return fmt.Errorf("missing content type for multipart form request")
+		}
+		req.Header.Set("Content-Type", contentType[0])
+	}
+
+	if hasToken {
+		req.Header.Set("Authorization", token)
+	}
+
+	return nil
+}
+
+// Do sends a request and returns the raw response body. Responses with a
+// status other than 200 are reported as one of the errors in errors.go.
+func (c *Client) Do(ctx context.Context, method, path string, body io.Reader, reqType RequestType, contentType ...string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.URL(path), body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if err := c.prepare(req, reqType, contentType...); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return data, newError(resp.StatusCode, resp.Header, data)
+	}
+
+	return data, nil
+}
+
+// send performs the request, waiting for the rate limiter before every attempt
+// and retrying idempotent requests according to c.Retry.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.httpClient().Do(req)
+		if attempt >= c.Retry.MaxRetries || !isIdempotent(req.Method) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := c.Retry.backoff(attempt)
+		if err == nil {
+			if !isRetryableStatus(resp.StatusCode) {
+				return resp, nil
+			}
+			if after, ok := retryAfter(resp.Header); ok {
+				if after > c.Retry.MaxDelay {
+					return resp, nil
+				}
+				delay = after
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Kilonova wraps every JSON payload in {"status": ..., "data": ...}.
+type envelope struct {
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data"`
+}
+
+func decode(data []byte, out any) error {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if env.Status != statusSuccess {
+		return newError(http.StatusOK, nil, data)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) call(ctx context.Context, method, path string, body io.Reader, reqType RequestType, out any, contentType ...string) error {
+	data, err := c.Do(ctx, method, path, body, reqType, contentType...)
+	if err != nil {
+		return err
+	}
+	return decode(data, out)
+}
+
+func (c *Client) get(ctx context.Context, path string, reqType RequestType, out any) error {
+	return c.call(ctx, http.MethodGet, path, nil, reqType, out)
+}
+
+func (c *Client) postJSON(ctx context.Context, path string, payload any, reqType RequestType, out any) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return c.call(ctx, http.MethodPost, path, bytes.NewReader(jsonData), reqType, out)
+}
+
+func (c *Client) postForm(ctx context.Context, path string, form url.Values, reqType RequestType, out any) error {
+	return c.call(ctx, http.MethodPost, path, strings.NewReader(form.Encode()), reqType, out)
+}
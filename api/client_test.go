@@ -0,0 +1,165 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var errBrokenToken = errors.New("broken token")
+
+func TestSubmitNeedsAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		tokenErr       error
+		wantErr        error
+		wantBeforeAuth bool
+		wantRequests   int
+	}{
+		{"signed in", "session-token", nil, nil, true, 1},
+		{"signed out", "", nil, ErrNotAuthenticated, false, 0},
+		{"unreadable token", "", errBrokenToken, errBrokenToken, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if got := r.Header.Get("Authorization"); got != tt.token {
+					t.Errorf("Authorization = %q, want %q", got, tt.token)
+				}
+				_, _ = w.Write([]byte(`{"status": "success", "data": 42}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL+"/", TokenFunc(func() (string, error) { return tt.token, tt.tokenErr }))
+			beforeAuth := false
+			client.BeforeAuth = func() { beforeAuth = true }
+
+			id, err := client.Submit(context.Background(), SubmitRequest{ProblemID: "1", Language: "cpp17", Filename: "main.cpp", Code: []byte("int main() {}")})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Submit error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && id != 42 {
+				t.Errorf("Submit = %d, want 42", id)
+			}
+			if beforeAuth != tt.wantBeforeAuth {
+				t.Errorf("BeforeAuth ran = %v, want %v", beforeAuth, tt.wantBeforeAuth)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestAuthenticatedPosts(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		call        func(c *Client) error
+		path        string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "set bio",
+			call:        func(c *Client) error { return c.SetBio(ctx, "hi") },
+			path:        "/api/user/self/setBio",
+			contentType: "application/json",
+			body:        `{"bio":"hi"}`,
+		},
+		{
+			name:        "change email",
+			call:        func(c *Client) error { return c.ChangeEmail(ctx, "a@b.ro", "secret") },
+			path:        "/api/user/changeEmail",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "email=a%40b.ro&password=secret",
+		},
+		{
+			name: "register",
+			call: func(c *Client) error {
+				_, err := c.RegisterContest(ctx, "12")
+				return err
+			},
+			path:        "/api/contest/12/register",
+			contentType: "application/json",
+			body:        "null",
+		},
+		{
+			name: "update problems",
+			call: func(c *Client) error {
+				_, err := c.UpdateContestProblems(ctx, "12", []int{1, 2})
+				return err
+			},
+			path:        "/api/contest/12/update/problems",
+			contentType: "application/json",
+			body:        `{"list":[1,2]}`,
+		},
+		{
+			name: "update setting",
+			call: func(c *Client) error {
+				_, err := c.UpdateContest(ctx, "12", "max_subs", "30")
+				return err
+			},
+			path:        "/api/contest/12/update",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "max_subs=30",
+		},
+		{
+			name: "answer question",
+			call: func(c *Client) error {
+				_, err := c.AnswerQuestion(ctx, "12", "3", "yes")
+				return err
+			},
+			path:        "/api/contest/12/answerQuestion",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "questionID=3&text=yes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if r.Method != http.MethodPost || r.URL.Path != tt.path {
+					t.Errorf("request = %s %s, want POST %s", r.Method, r.URL.Path, tt.path)
+				}
+				if got := r.Header.Get("Content-Type"); got != tt.contentType {
+					t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+				}
+				if string(body) != tt.body {
+					t.Errorf("body = %s, want %s", body, tt.body)
+				}
+				if got := r.Header.Get("Authorization"); got != "session-token" {
+					t.Errorf("Authorization = %q, want the session token", got)
+				}
+				_, _ = w.Write([]byte(`{"status": "success", "data": "done"}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL+"/", TokenFunc(func() (string, error) { return "session-token", nil }))
+			beforeAuth := false
+			client.BeforeAuth = func() { beforeAuth = true }
+			if err := tt.call(client); err != nil {
+				t.Fatal(err)
+			}
+			if !beforeAuth {
+				t.Error("BeforeAuth didn't run")
+			}
+
+			guest := NewClient(server.URL+"/", nil)
+			if err := tt.call(guest); !errors.Is(err, ErrNotAuthenticated) {
+				t.Errorf("signed out error = %v, want %v", err, ErrNotAuthenticated)
+			}
+		})
+	}
+}
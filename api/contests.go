@@ -0,0 +1,139 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+func (c *Client) GetContest(ctx context.Context, id string) (*Contest, error) {
+	var contest Contest
+	if err := c.get(ctx, fmt.Sprintf(URL_CONTEST, id), RequestInfo, &contest); err != nil {
+		return nil, err
+	}
+	return &contest, nil
+}
+
+func (c *Client) ContestProblems(ctx context.Context, id string) ([]Problem, error) {
+	var problems []Problem
+	if err := c.get(ctx, fmt.Sprintf(URL_CONTEST_PROBLEMS, id), RequestNone, &problems); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+func (c *Client) Announcements(ctx context.Context, contestID string) ([]Announcement, error) {
+	var announcements []Announcement
+	if err := c.get(ctx, fmt.Sprintf(URL_CONTEST_ANNOUNCEMENTS, contestID), RequestNone, &announcements); err != nil {
+		return nil, err
+	}
+	return announcements, nil
+}
+
+// Questions returns the current user's questions, or every question in the
+// contest when all is set (contest organizers only).
+func (c *Client) Questions(ctx context.Context, contestID string, all bool) ([]Question, error) {
+	path := URL_CONTEST_YOUR_QUESTIONS
+	if all {
+		path = URL_CONTEST_ALL_QUESTIONS
+	}
+
+	var questions []Question
+	if err := c.get(ctx, fmt.Sprintf(path, contestID), RequestNone, &questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+func (c *Client) Leaderboard(ctx context.Context, contestID string) (*Leaderboard, error) {
+	var leaderboard Leaderboard
+	if err := c.get(ctx, fmt.Sprintf(URL_CONTEST_LEADERBOARD, contestID), RequestNone, &leaderboard); err != nil {
+		return nil, err
+	}
+	return &leaderboard, nil
+}
+
+// LeaderboardCSV downloads the leaderboard export of a contest.
+func (c *Client) LeaderboardCSV(ctx context.Context, contestID string) ([]byte, error) {
+	return c.Do(ctx, http.MethodGet, fmt.Sprintf(URL_CONTEST_ASSETS, contestID), nil, RequestDownloadZip)
+}
+
+// CreateContest creates a contest and returns its ID.
+func (c *Client) CreateContest(ctx context.Context, name, contestType string) (int, error) {
+	form := url.Values{
+		"name": {name},
+		"type": {contestType},
+	}
+
+	var id int
+	if err := c.postForm(ctx, URL_CONTEST_CREATE, form, RequestFormAuth, &id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// contestAction posts to one of the contest endpoints that take no
+// parameters and returns the server's message.
+func (c *Client) contestAction(ctx context.Context, path, contestID string) (string, error) {
+	var message string
+	err := c.postJSON(ctx, fmt.Sprintf(path, contestID), nil, RequestJSONAuth, &message)
+	return message, err
+}
+
+func (c *Client) RegisterContest(ctx context.Context, contestID string) (string, error) {
+	return c.contestAction(ctx, URL_CONTEST_REGISTER, contestID)
+}
+
+func (c *Client) StartContest(ctx context.Context, contestID string) (string, error) {
+	return c.contestAction(ctx, URL_CONTEST_START, contestID)
+}
+
+func (c *Client) DeleteContest(ctx context.Context, contestID string) (string, error) {
+	return c.contestAction(ctx, URL_CONTEST_DELETE, contestID)
+}
+
+// UpdateContest changes one setting of a contest, named by its form field.
+func (c *Client) UpdateContest(ctx context.Context, contestID, field, value string) (string, error) {
+	return c.contestForm(ctx, URL_CONTEST_UPDATE, contestID, url.Values{field: {value}})
+}
+
+// UpdateContestProblems replaces the problems of a contest.
+func (c *Client) UpdateContestProblems(ctx context.Context, contestID string, problemIDs []int) (string, error) {
+	var message string
+	err := c.postJSON(ctx, fmt.Sprintf(URL_CONTEST_UPDATE_PROBLEMS, contestID), map[string][]int{"list": problemIDs}, RequestJSONAuth, &message)
+	return message, err
+}
+
+// contestForm posts a form to a contest endpoint and returns the server's
+// message.
+func (c *Client) contestForm(ctx context.Context, path, contestID string, form url.Values) (string, error) {
+	var message string
+	err := c.postForm(ctx, fmt.Sprintf(path, contestID), form, RequestFormAuth, &message)
+	return message, err
+}
+
+func (c *Client) CreateAnnouncement(ctx context.Context, contestID, text string) (string, error) {
+	return c.contestForm(ctx, URL_CONTEST_CREATE_ANNOUNCEMENT, contestID, url.Values{"text": {text}})
+}
+
+func (c *Client) UpdateAnnouncement(ctx context.Context, contestID, announcementID, text string) (string, error) {
+	return c.contestForm(ctx, URL_CONTEST_UPDATE_ANNOUNCEMENT, contestID, url.Values{"id": {announcementID}, "text": {text}})
+}
+
+func (c *Client) DeleteAnnouncement(ctx context.Context, contestID, announcementID string) (string, error) {
+	return c.contestForm(ctx, URL_CONTEST_DELETE_ANNOUNCEMENT, contestID, url.Values{"id": {announcementID}})
+}
+
+func (c *Client) AskQuestion(ctx context.Context, contestID, text string) (string, error) {
+	return c.contestForm(ctx, URL_CONTEST_ASK_QUESTION, contestID, url.Values{"text": {text}})
+}
+
+func (c *Client) AnswerQuestion(ctx context.Context, contestID, questionID, text string) (string, error) {
+	return c.contestForm(ctx, URL_CONTEST_RESPOND_QUESTION, contestID, url.Values{"questionID": {questionID}, "text": {text}})
+}
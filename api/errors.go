@@ -0,0 +1,91 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError is returned when Kilonova rejects a request. Depending on the HTTP
+// status it is wrapped in one of the more specific types below, so callers can
+// use errors.As with either the specific type or *APIError.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// NotFoundError is returned for 404 responses.
+type NotFoundError struct{ APIError }
+
+// UnauthorizedError is returned for 401 responses, usually a missing or expired session.
+type UnauthorizedError struct{ APIError }
+
+// ForbiddenError is returned for 403 responses.
+type ForbiddenError struct{ APIError }
+
+// RateLimitedError is returned for 429 responses. RetryAfter is zero when the
+// server did not say how long to wait.
+type RateLimitedError struct {
+	APIError
+	RetryAfter time.Duration
+}
+
+// ValidationError is returned for 400 and 422 responses, when the server
+// refused the request parameters.
+type ValidationError struct{ APIError }
+
+// ServerError is returned for 5xx responses.
+type ServerError struct{ APIError }
+
+func (e *NotFoundError) Unwrap() error     { return &e.APIError }
+func (e *UnauthorizedError) Unwrap() error { return &e.APIError }
+func (e *ForbiddenError) Unwrap() error    { return &e.APIError }
+func (e *RateLimitedError) Unwrap() error  { return &e.APIError }
+func (e *ValidationError) Unwrap() error   { return &e.APIError }
+func (e *ServerError) Unwrap() error       { return &e.APIError }
+
+func newError(statusCode int, header http.Header, body []byte) error {
+	base := APIError{StatusCode: statusCode, Message: errorMessage(body)}
+
+	switch {
+	case statusCode == http.StatusNotFound:
+		return &NotFoundError{base}
+	case statusCode == http.StatusUnauthorized:
+		return &UnauthorizedError{base}
+	case statusCode == http.StatusForbidden:
+		return &ForbiddenError{base}
+	case statusCode == http.StatusTooManyRequests:
+		after, _ := retryAfter(header)
+		return &RateLimitedError{APIError: base, RetryAfter: after}
+	case statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity:
+		return &ValidationError{base}
+	case statusCode >= 500:
+		return &ServerError{base}
+	default:
+		return &base
+	}
+}
+
+func errorMessage(body []byte) string {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil || len(env.Data) == 0 {
+		return strings.TrimSpace(string(body))
+	}
+
+	var message string
+	if err := json.Unmarshal(env.Data, &message); err == nil {
+		return message
+	}
+	return string(env.Data)
+}
@@ -0,0 +1,317 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Fixtures are JSON files holding one request and its response. They are
+// named after the request, so the same request made several times (like
+// polling a submission) is stored as numbered fixtures and replayed in order.
+
+const redacted = "REDACTED"
+
+// Headers that may carry credentials and are never written to a fixture.
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// Endpoints whose response data is a session token.
+var tokenEndpoints = []string{URL_LOGIN}
+
+type fixture struct {
+	Request  fixtureRequest  `json:"request"`
+	Response fixtureResponse `json:"response"`
+}
+
+type fixtureRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   fixtureBody `json:"body"`
+}
+
+type fixtureResponse struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       fixtureBody `json:"body"`
+}
+
+// fixtureBody keeps text bodies readable and stores binary ones (archives) as base64.
+type fixtureBody struct {
+	Text   string `json:"text,omitempty"`
+	Base64 string `json:"base64,omitempty"`
+}
+
+func newFixtureBody(data []byte) fixtureBody {
+	if utf8.Valid(data) {
+		return fixtureBody{Text: string(data)}
+	}
+	return fixtureBody{Base64: base64.StdEncoding.EncodeToString(data)}
+}
+
+func (b fixtureBody) bytes() ([]byte, error) {
+	if b.Base64 != "" {
+		return base64.StdEncoding.DecodeString(b.Base64)
+	}
+	return []byte(b.Text), nil
+}
+
+// fixtureSequence hands out the numbered file names of each request.
+type fixtureSequence struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (s *fixtureSequence) next(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.calls == nil {
+		s.calls = map[string]int{}
+	}
+	s.calls[name]++
+	return s.calls[name]
+}
+
+func fixtureFile(dir, name string, n int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%03d.json", name, n))
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// fixtureName identifies a request by method, path, query and (redacted)
+// body, but not by host or credentials, so fixtures recorded against one
+// instance replay against any base URL and any account.
+func fixtureName(req *http.Request, body []byte) string {
+	sum := sha256.Sum256([]byte(req.URL.RequestURI() + "\n" + string(normalizeBody(req, body))))
+	slug := strings.Trim(unsafeFileChars.ReplaceAllString(req.URL.Path, "_"), "_")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	return req.Method + "_" + slug + "_" + hex.EncodeToString(sum[:4])
+}
+
+// normalizeBody removes the parts of a request body that change between runs.
+func normalizeBody(req *http.Request, body []byte) []byte {
+	body = redactBody(req, body)
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err == nil && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), []byte("BOUNDARY"))
+	}
+	return body
+}
+
+func isSecretField(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "token")
+}
+
+// redactBody masks password fields of form and JSON request bodies.
+func redactBody(req *http.Request, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	switch {
+	case strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded"):
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		for key := range form {
+			if isSecretField(key) {
+				form.Set(key, redacted)
+			}
+		}
+		return []byte(form.Encode())
+
+	case strings.HasPrefix(req.Header.Get("Content-Type"), "application/json"):
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return body
+		}
+		for key := range fields {
+			if isSecretField(key) {
+				fields[key] = redacted
+			}
+		}
+		if masked, err := json.Marshal(fields); err == nil {
+			return masked
+		}
+	}
+	return body
+}
+
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+// redactResponse masks the request token wherever it is echoed and the data
+// of responses that hand out a new token.
+func redactResponse(req *http.Request, body []byte) []byte {
+	if token := req.Header.Get("Authorization"); token != "" {
+		body = bytes.ReplaceAll(body, []byte(token), []byte(redacted))
+	}
+
+	for _, endpoint := range tokenEndpoints {
+		if !strings.HasSuffix(req.URL.Path, "/"+endpoint) {
+			continue
+		}
+		var env map[string]any
+		if json.Unmarshal(body, &env) == nil && env["data"] != nil {
+			env["data"] = redacted
+			if masked, err := json.Marshal(env); err == nil {
+				return masked
+			}
+		}
+	}
+	return body
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// RecordTransport forwards requests to Next and saves each exchange, with
+// credentials redacted, as a fixture in Dir.
+type RecordTransport struct {
+	Dir  string
+	Next http.RoundTripper
+
+	seq fixtureSequence
+}
+
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	// The body may shrink or grow when redacted.
+	respHeader := redactHeader(resp.Header)
+	respHeader.Del("Content-Length")
+
+	name := fixtureName(req, reqBody)
+	record := fixture{
+		Request: fixtureRequest{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Header: redactHeader(req.Header),
+			Body:   newFixtureBody(redactBody(req, reqBody)),
+		},
+		Response: fixtureResponse{
+			StatusCode: resp.StatusCode,
+			Header:     respHeader,
+			Body:       newFixtureBody(redactResponse(req, respBody)),
+		},
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(fixtureFile(t.Dir, name, t.seq.next(name)), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ReplayTransport answers requests from the fixtures in Dir and never uses
+// the network. Once the fixtures of a request run out, the last one is
+// served again.
+type ReplayTransport struct {
+	Dir string
+
+	seq fixtureSequence
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fixtureName(req, reqBody)
+	n := t.seq.next(name)
+	data, err := os.ReadFile(fixtureFile(t.Dir, name, n))
+	for os.IsNotExist(err) && n > 1 {
+		n--
+		data, err = os.ReadFile(fixtureFile(t.Dir, name, n))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no recorded response for %s %s in %s", req.Method, req.URL.RequestURI(), t.Dir)
+	}
+
+	var record fixture
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", fixtureFile(t.Dir, name, n), err)
+	}
+	body, err := record.Response.Body.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", fixtureFile(t.Dir, name, n), err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", record.Response.StatusCode, http.StatusText(record.Response.StatusCode)),
+		StatusCode:    record.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        record.Response.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
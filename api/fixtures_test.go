@@ -0,0 +1,160 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"form password", "application/x-www-form-urlencoded", "password=hunter2&username=ana", "password=REDACTED&username=ana"},
+		{"form token", "application/x-www-form-urlencoded", "new_token=abc", "new_token=REDACTED"},
+		{"json password", "application/json", `{"old_password":"hunter2","bio":"hi"}`, `{"bio":"hi","old_password":"REDACTED"}`},
+		{"json without secrets", "application/json", `{"bio":"hi"}`, `{"bio":"hi"}`},
+		{"invalid json is kept", "application/json", `{"password":`, `{"password":`},
+		{"other content types are kept", "text/plain", "password=hunter2", "password=hunter2"},
+		{"empty", "application/json", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("Content-Type", tt.contentType)
+			if got := string(redactBody(req, []byte(tt.body))); got != tt.want {
+				t.Errorf("redactBody = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "secret")
+	header.Set("Cookie", "kn-sessionid=secret")
+	header.Set("Set-Cookie", "kn-sessionid=secret")
+	header.Set("Accept", "application/json")
+
+	redactedHeader := redactHeader(header)
+	for _, name := range redactedHeaders {
+		if got := redactedHeader.Get(name); got != redacted {
+			t.Errorf("%s = %q, want %q", name, got, redacted)
+		}
+	}
+	if got := redactedHeader.Get("Accept"); got != "application/json" {
+		t.Errorf("Accept = %q, want it unchanged", got)
+	}
+	if header.Get("Authorization") != "secret" {
+		t.Error("redactHeader modified the original header")
+	}
+}
+
+func TestRedactResponse(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		body  string
+		want  string
+	}{
+		{"echoed token", "/api/user/self", "secret", `{"status":"success","data":{"session":"secret"}}`, `{"status":"success","data":{"session":"REDACTED"}}`},
+		{"login", "/" + URL_LOGIN, "", `{"status":"success","data":"new-session"}`, `{"data":"REDACTED","status":"success"}`},
+		{"other endpoints", "/api/problem/1", "", `{"status":"success","data":"x"}`, `{"status":"success","data":"x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			if got := string(redactResponse(req, []byte(tt.body))); got != tt.want {
+				t.Errorf("redactResponse = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Set-Cookie", "kn-sessionid=server-secret")
+		if strings.HasSuffix(r.URL.Path, URL_LOGIN) {
+			fmt.Fprint(w, `{"status": "success", "data": "server-secret"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status": "success", "data": %d}`, calls)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	tokens := TokenFunc(func() (string, error) { return "client-secret", nil })
+
+	recorder := NewClient(server.URL+"/", tokens)
+	recorder.HTTPClient = &http.Client{Transport: &RecordTransport{Dir: dir}}
+
+	form := url.Values{"username": {"ana"}, "password": {"hunter2"}}
+	if err := recorder.postForm(ctx, URL_LOGIN, form, RequestFormGuest, nil); err != nil {
+		t.Fatal(err)
+	}
+	var recorded []int
+	for range 2 {
+		var n int
+		if err := recorder.get(ctx, "api/counter", RequestNone, &n); err != nil {
+			t.Fatal(err)
+		}
+		recorded = append(recorded, n)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("recorded %d fixtures, want 3", len(files))
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"client-secret", "server-secret", "hunter2"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s contains %q", filepath.Base(file), secret)
+			}
+		}
+	}
+
+	// Replayed against another base URL, without network or credentials.
+	replayer := NewClient("http://replay.invalid/", nil)
+	replayer.HTTPClient = &http.Client{Transport: &ReplayTransport{Dir: dir}}
+	for i, want := range append(recorded, recorded[len(recorded)-1]) {
+		var n int
+		if err := replayer.get(ctx, "api/counter", RequestNone, &n); err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("replay %d = %d, want %d", i+1, n, want)
+		}
+	}
+
+	if err := replayer.get(ctx, "api/other", RequestNone, nil); err == nil {
+		t.Error("replaying a request that was never recorded succeeded")
+	}
+}
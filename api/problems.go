@@ -0,0 +1,93 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func (c *Client) GetProblem(ctx context.Context, id string) (*Problem, error) {
+	var problem Problem
+	if err := c.getCached(ctx, fmt.Sprintf(URL_PROBLEM, id), RequestNone, c.CacheTTL.Problem, &problem, nil); err != nil {
+		return nil, err
+	}
+	return &problem, nil
+}
+
+// ListProblems returns every problem visible to the current user.
+func (c *Client) ListProblems(ctx context.Context) ([]Problem, error) {
+	var problems []Problem
+	if err := c.postJSON(ctx, fmt.Sprintf(URL_PROBLEM, "get"), nil, RequestJSON, &problems); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+// SearchProblems returns one page (50 problems) of search results.
+func (c *Client) SearchProblems(ctx context.Context, filter ProblemFilter) (*SearchResult, error) {
+	var result SearchResult
+	if err := c.postJSON(ctx, URL_SEARCH, filter, RequestJSON, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) ProblemLanguages(ctx context.Context, id string) ([]Language, error) {
+	var langs []Language
+	if err := c.getCached(ctx, fmt.Sprintf(URL_LANGS_PB, id), RequestNone, c.CacheTTL.Languages, &langs, nil); err != nil {
+		return nil, err
+	}
+	return langs, nil
+}
+
+// StatementLanguages are the languages statements can be written in.
+var StatementLanguages = []string{"RO", "EN"}
+
+// StatementAttachment returns the name of the attachment holding the
+// statement in the given language.
+func StatementAttachment(lang string) (string, error) {
+	switch strings.ToUpper(lang) {
+	case "RO":
+		return STAT_FILENAME_RO, nil
+	case "EN":
+		return STAT_FILENAME_EN, nil
+	default:
+		return "", fmt.Errorf("invalid language chosen: %q. Must be 'RO' or 'EN'", lang)
+	}
+}
+
+func StatementPath(id, lang string) (string, error) {
+	attachment, err := StatementAttachment(lang)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(URL_STATEMENT, id, attachment), nil
+}
+
+// Statement returns the decoded markdown statement of a problem in the given
+// language ("RO" or "EN").
+func (c *Client) Statement(ctx context.Context, id, lang string) (string, error) {
+	path, err := StatementPath(id, lang)
+	if err != nil {
+		return "", err
+	}
+
+	var attachment struct {
+		Data []byte `json:"data"`
+	}
+	if err := c.getCached(ctx, path, RequestNone, c.CacheTTL.Statement, &attachment, nil); err != nil {
+		return "", err
+	}
+	return string(attachment.Data), nil
+}
+
+// ProblemArchive downloads the zipped tests and attachments of a problem.
+func (c *Client) ProblemArchive(ctx context.Context, id string) ([]byte, error) {
+	return c.Do(ctx, http.MethodGet, fmt.Sprintf(URL_ASSETS, id), nil, RequestDownloadZip)
+}
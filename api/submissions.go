@@ -0,0 +1,88 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+)
+
+// GetSubmission returns a submission. Finished submissions no longer change,
+// so they are cached forever.
+func (c *Client) GetSubmission(ctx context.Context, id string) (*Submission, error) {
+	var submission Submission
+	finished := func() bool { return submission.Status == "finished" }
+	if err := c.getCached(ctx, fmt.Sprintf(URL_LATEST_SUBMISSION, id), RequestNone, Forever, &submission, finished); err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func submissionListPath(filter SubmissionFilter) string {
+	noUser := filter.UserID == "" || filter.UserID == "all"
+	noProblem := filter.ProblemID == "" || filter.ProblemID == "all"
+
+	switch {
+	case noUser && noProblem:
+		return fmt.Sprintf(URL_SUBMISSION_LIST_NO_FILTER, filter.Offset)
+	case noUser:
+		return fmt.Sprintf(URL_SUBMISSION_LIST_NO_USER, filter.Offset, filter.ProblemID)
+	case noProblem:
+		return fmt.Sprintf(URL_SUBMISSION_LIST_NO_PROBLEM, filter.Offset, filter.UserID)
+	default:
+		return fmt.Sprintf(URL_SUBMISSION_LIST, filter.Offset, filter.ProblemID, filter.UserID)
+	}
+}
+
+// ListSubmissions returns one page (50 submissions) starting at filter.Offset.
+func (c *Client) ListSubmissions(ctx context.Context, filter SubmissionFilter) (*SubmissionList, error) {
+	var list SubmissionList
+	if err := c.get(ctx, submissionListPath(filter), RequestFormAuth, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Submit uploads a solution and returns the ID of the new submission.
+func (c *Client) Submit(ctx context.Context, submit SubmitRequest) (int, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	if err := writer.WriteField("problem_id", submit.ProblemID); err != nil {
+		return 0, fmt.Errorf("failed to write problem_id field: %w", err)
+	}
+	if err := writer.WriteField("language", submit.Language); err != nil {
+		return 0, fmt.Errorf("failed to write language field: %w", err)
+	}
+
+	fileWriter, err := writer.CreateFormFile("code", submit.Filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create form file for code: %w", err)
+	}
+	if _, err := fileWriter.Write(submit.Code); err != nil {
+		return 0, fmt.Errorf("failed to copy code file content: %w", err)
+	}
+
+	if submit.ContestID != "" {
+		if err := writer.WriteField("contestID", submit.ContestID); err != nil {
+			return 0, fmt.Errorf("failed to write contestID field: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close writer: %w", err)
+	}
+
+	var id int
+	err = c.call(ctx, http.MethodPost, URL_SUBMIT, &body, RequestMultipartForm, &id, writer.FormDataContentType())
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Trace levels
+const (
+	TraceOff = iota
+	TraceRequests
+	TraceBodies
+)
+
+// Bodies longer than this are cut short in traces.
+const maxTracedBody = 4096
+
+// TraceTransport logs every request sent through Next to Out. TraceRequests
+// prints the method, URL, status, timing and sizes; TraceBodies adds headers
+// and bodies. Tokens, cookies and passwords are always masked.
+type TraceTransport struct {
+	Next  http.RoundTripper
+	Out   io.Writer
+	Level int
+
+	mu sync.Mutex
+}
+
+func (t *TraceTransport) logf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.Out, format, args...)
+}
+
+func (t *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if t.Level <= TraceOff {
+		return next.RoundTrip(req)
+	}
+
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.logf("--> %s %s (%d bytes)\n", req.Method, req.URL, len(reqBody))
+	if t.Level >= TraceBodies {
+		t.logf("%s", traceHeader("-->", redactHeader(req.Header)))
+		t.logf("%s", traceBody("-->", redactBody(req, reqBody)))
+	}
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.logf("<-- %s %s failed after %s: %v\n", req.Method, req.URL, elapsed, err)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.logf("<-- %s %s failed reading body after %s: %v\n", req.Method, req.URL, elapsed, err)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.logf("<-- %s %s %s (%s, %d bytes)\n", resp.Status, req.Method, req.URL, elapsed, len(respBody))
+	if t.Level >= TraceBodies {
+		t.logf("%s", traceHeader("<--", redactHeader(resp.Header)))
+		t.logf("%s", traceBody("<--", redactResponse(req, respBody)))
+	}
+
+	return resp, nil
+}
+
+func traceHeader(prefix string, header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s %s: %s\n", prefix, name, strings.Join(header[name], ", "))
+	}
+	return b.String()
+}
+
+func traceBody(prefix string, body []byte) string {
+	switch {
+	case len(body) == 0:
+		return ""
+	case !isText(body):
+		return fmt.Sprintf("%s <%d bytes of binary data>\n", prefix, len(body))
+	case len(body) > maxTracedBody:
+		return fmt.Sprintf("%s %s... (%d more bytes)\n", prefix, body[:maxTracedBody], len(body)-maxTracedBody)
+	default:
+		return fmt.Sprintf("%s %s\n", prefix, bytes.TrimSpace(body))
+	}
+}
+
+func isText(body []byte) bool {
+	return utf8.Valid(body) && !bytes.ContainsRune(body, 0)
+}
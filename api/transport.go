@@ -0,0 +1,85 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// HTTPOptions configures the *http.Client built by NewHTTPClient.
+type HTTPOptions struct {
+	// ConnectTimeout bounds dialing and the TLS handshake.
+	ConnectTimeout time.Duration
+	// ResponseTimeout bounds the wait for response headers once a request is sent.
+	ResponseTimeout time.Duration
+	// Timeout bounds a whole exchange, including reading the body. Zero means no limit.
+	Timeout time.Duration
+	// Proxy is used instead of the HTTP_PROXY/HTTPS_PROXY environment variables when set.
+	Proxy string
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string
+}
+
+var DefaultHTTPOptions = HTTPOptions{
+	ConnectTimeout:  10 * time.Second,
+	ResponseTimeout: 30 * time.Second,
+	Timeout:         5 * time.Minute,
+}
+
+// NewHTTPClient returns a client that keeps connections alive between
+// requests and accepts gzip-compressed responses.
+func NewHTTPClient(opts HTTPOptions) (*http.Client, error) {
+	dialer := &net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   opts.ConnectTimeout,
+		ResponseHeaderTimeout: opts.ResponseTimeout,
+		ExpectContinueTimeout: time.Second,
+		// Leaving DisableCompression unset makes the transport ask for gzip and
+		// decompress responses transparently.
+	}
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", opts.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	}
+
+	return &http.Client{Transport: transport, Timeout: opts.Timeout}, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import "encoding/json"
+
+// Problems
+
+type Problem struct {
+	Id            int     `json:"id"`
+	Name          string  `json:"name"`
+	Time          float64 `json:"time_limit"`
+	MemoryLimit   int     `json:"memory_limit"`
+	SourceSize    int     `json:"source_size"`
+	SourceCredits string  `json:"source_credits"`
+	MaxScore      int     `json:"max_score"`
+}
+
+type ProblemFilter struct {
+	NameFuzzy string `json:"name_fuzzy"`
+	Offset    int    `json:"offset"`
+}
+
+type SearchResult struct {
+	Count    int       `json:"count"`
+	Problems []Problem `json:"problems"`
+}
+
+type Language struct {
+	Name string `json:"internal_name"`
+}
+
+// Submissions
+
+type Subtest struct {
+	ID         int     `json:"id"`
+	Done       bool    `json:"done"`
+	Skipped    bool    `json:"skipped"`
+	Verdict    string  `json:"verdict"`
+	Time       float64 `json:"time"`
+	Memory     int     `json:"memory"`
+	Percentage int     `json:"percentage"`
+	TestID     int     `json:"test_id"`
+	Score      int     `json:"score"`
+}
+
+type Submission struct {
+	Id             int       `json:"id"`
+	CreatedAt      string    `json:"created_at"`
+	UserID         int       `json:"user_id"`
+	ProblemID      int       `json:"problem_id"`
+	Language       string    `json:"language"`
+	Status         string    `json:"status"`
+	CompileError   bool      `json:"compile_error"`
+	ContestID      *int      `json:"contest_id"`
+	MaxTime        float64   `json:"max_time"`
+	MaxMemory      int       `json:"max_memory"`
+	Score          float64   `json:"score"`
+	CompileMessage string    `json:"compile_message"`
+	Code           string    `json:"code"`
+	Subtests       []Subtest `json:"subtests"`
+}
+
+type SubmissionList struct {
+	Submissions []Submission `json:"submissions"`
+	Count       int          `json:"count"`
+}
+
+// SubmissionFilter selects submissions by problem and user; "all" (or an
+// empty string) disables a filter.
+type SubmissionFilter struct {
+	ProblemID string
+	UserID    string
+	Offset    int
+}
+
+type SubmitRequest struct {
+	ProblemID string
+	Language  string
+	Filename  string
+	Code      []byte
+	ContestID string
+}
+
+// Users
+
+type User struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Admin       bool   `json:"admin"`
+	Proposer    bool   `json:"proposer"`
+	DisplayName string `json:"display_name"`
+}
+
+type SolvedProblem struct {
+	ProblemId int     `json:"id"`
+	Name      string  `json:"name"`
+	Source    string  `json:"source_credits"`
+	Score     float64 `json:"score_scale"`
+}
+
+// Contests
+
+type Contest struct {
+	StartTime             string      `json:"start_time"`
+	EndTime               string      `json:"end_time"`
+	MaxSubs               int         `json:"max_subs"`
+	Name                  string      `json:"name"`
+	Visible               bool        `json:"visible"`
+	PublicLeaderboard     bool        `json:"public_leaderboard"`
+	ChangeLeadboardFreeze bool        `json:"change_leaderboard_freeze"`
+	IcpcSubmPenalty       json.Number `json:"icpc_submission_penalty"`
+	LeadAdvFilter         bool        `json:"leaderboard_advanced_filter"`
+	LeadStyle             string      `json:"leaderboard_style"`
+	PerUserTime           json.Number `json:"per_user_time"`
+	PublicJoin            bool        `json:"public_join"`
+	QuestionCoolDown      json.Number `json:"question_contest"`
+	RegisterDuringContest bool        `json:"register_during_contest"`
+	SubmissionCooldown    json.Number `json:"submission_cooldown"`
+}
+
+type Announcement struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+	Time string `json:"created_at"`
+}
+
+type Question struct {
+	Id            int    `json:"id"`
+	AuthorID      int    `json:"author_id"`
+	Text          string `json:"text"`
+	Time          string `json:"asked_at"`
+	RespondedTime string `json:"responded_at"`
+	Response      string `json:"response"`
+}
+
+type LeaderboardEntry struct {
+	User struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"user"`
+	Scores map[string]int `json:"scores"`
+	Total  int            `json:"total"`
+}
+
+type Leaderboard struct {
+	ProblemNames map[string]string  `json:"problem_names"`
+	Entries      []LeaderboardEntry `json:"entries"`
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Login returns a new session token for the given credentials.
+func (c *Client) Login(ctx context.Context, username, password string) (string, error) {
+	form := url.Values{
+		"username": {username},
+		"password": {password},
+	}
+
+	var token string
+	if err := c.postForm(ctx, URL_LOGIN, form, RequestFormGuest, &token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (c *Client) Logout(ctx context.Context) error {
+	return c.call(ctx, http.MethodPost, URL_LOGOUT, bytes.NewBufferString(`{"key": "value"}`), RequestFormAuth, nil)
+}
+
+// ExtendSession extends the current session and returns its new expiry time.
+func (c *Client) ExtendSession(ctx context.Context) (time.Time, error) {
+	var expires string
+	if err := c.call(ctx, http.MethodPost, URL_EXTEND_SESSION, nil, RequestFormAuth, &expires); err != nil {
+		return time.Time{}, err
+	}
+
+	expiry, err := time.Parse(time.RFC3339Nano, expires)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse session expiry %q: %w", expires, err)
+	}
+	return expiry, nil
+}
+
+func (c *Client) Self(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.get(ctx, URL_SELF, RequestFormAuth, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) GetUser(ctx context.Context, id string) (*User, error) {
+	var user User
+	if err := c.get(ctx, fmt.Sprintf(URL_USER, id), RequestFormGuest, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) SelfSolvedProblems(ctx context.Context) ([]SolvedProblem, error) {
+	var problems []SolvedProblem
+	if err := c.get(ctx, URL_SELF_PROBLEMS, RequestFormAuth, &problems); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+func (c *Client) SolvedProblems(ctx context.Context, userID string) ([]SolvedProblem, error) {
+	var problems []SolvedProblem
+	if err := c.get(ctx, fmt.Sprintf(URL_USER_PROBLEMS, userID), RequestFormGuest, &problems); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+// UserBio scrapes the bio from a user's public profile page, which the API
+// does not expose.
+func (c *Client) UserBio(ctx context.Context, username string) (string, error) {
+	page, err := c.Do(ctx, http.MethodGet, fmt.Sprintf(URL_PROFILE, url.PathEscape(username)), nil, RequestNone)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse profile page: %w", err)
+	}
+
+	return doc.Find("div.segment-panel.reset-list.statement-content.enhance-tables p").First().Text(), nil
+}
+
+func (c *Client) SetBio(ctx context.Context, bio string) error {
+	return c.postJSON(ctx, URL_SELF_SET_BIO, map[string]string{"bio": bio}, RequestJSONAuth, nil)
+}
+
+func (c *Client) ChangeName(ctx context.Context, newName, password string) error {
+	payload := map[string]string{
+		"newName":  newName,
+		"password": password,
+	}
+	return c.postJSON(ctx, URL_CHANGE_NAME, payload, RequestJSONAuth, nil)
+}
+
+// ChangePassword changes the password of the current user. The server ends
+// the session afterwards.
+func (c *Client) ChangePassword(ctx context.Context, oldPassword, newPassword string) error {
+	payload := map[string]string{
+		"old_password": oldPassword,
+		"password":     newPassword,
+	}
+	return c.postJSON(ctx, URL_CHANGE_PASS, payload, RequestJSONAuth, nil)
+}
+
+func (c *Client) ChangeEmail(ctx context.Context, email, password string) error {
+	form := url.Values{
+		"email":    {email},
+		"password": {password},
+	}
+	return c.postForm(ctx, URL_CHANGE_EMAIL, form, RequestFormAuth, nil)
+}
+
+// ResetPassword asks for a password reset email, which is sent as a guest,
+// and returns the server's message.
+func (c *Client) ResetPassword(ctx context.Context, email string) (string, error) {
+	var message string
+	err := c.postForm(ctx, URL_CHANGE_PASS, url.Values{"email": {email}}, RequestFormGuest, &message)
+	return message, err
+}
+
+// ResendEmail sends the verification email of the current user again and
+// returns the server's message.
+func (c *Client) ResendEmail(ctx context.Context) (string, error) {
+	var message string
+	err := c.call(ctx, http.MethodPost, URL_RESEND_MAIL, nil, RequestFormAuth, &message)
+	return message, err
+}
+
+// DeleteUser deletes an account. Only administrators may use it.
+func (c *Client) DeleteUser(ctx context.Context, userID string) (string, error) {
+	var message string
+	err := c.postForm(ctx, URL_DELETE_USER, url.Values{"id": {userID}}, RequestFormAuth, &message)
+	return message, err
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package config
+
+import (
+	"fmt"
+	"kncli/internal"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var listAll = false
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config [command] ...",
+	Short: "View and change settings in config.yaml",
+	Long: `View and change settings in config.yaml. Values given as flags or environment
+variables take precedence over the config file. With a profile other than the default one,
+its own config.yaml overrides the global file and is the one changed by 'set' and 'edit'.`,
+}
+
+var getConfigCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print the value of a setting.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		getConfig(args[0])
+	},
+}
+
+var setConfigCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Change a setting. An empty value removes it.",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		setConfig(args[0], args[1])
+	},
+}
+
+var listConfigCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the settings in use.",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		listConfig()
+	},
+}
+
+var editConfigCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open config.yaml in $VISUAL or $EDITOR.",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		editConfig()
+	},
+}
+
+func init() {
+	listConfigCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Also list the keys that are not set, with a description.")
+
+	ConfigCmd.AddCommand(getConfigCmd)
+	ConfigCmd.AddCommand(setConfigCmd)
+	ConfigCmd.AddCommand(listConfigCmd)
+	ConfigCmd.AddCommand(editConfigCmd)
+}
+
+func checkKey(key string) {
+	if _, ok := internal.ConfigKeys[key]; !ok {
+		internal.LogError(fmt.Errorf("unknown config key %q, see 'config list --all'", key))
+	}
+}
+
+// writableConfigFile returns the file changed by 'set' and 'edit'.
+func writableConfigFile() string {
+	files := internal.ConfigFiles()
+	return files[len(files)-1]
+}
+
+func getConfig(key string) {
+	checkKey(key)
+
+	value, ok := internal.ConfigValue(key)
+	if !ok {
+		internal.LogError(fmt.Errorf("%s is not set", key))
+	}
+	fmt.Println(value)
+}
+
+func setConfig(key, value string) {
+	checkKey(key)
+
+	file := writableConfigFile()
+	if err := internal.SetConfigValue(file, key, value); err != nil {
+		internal.LogError(fmt.Errorf("failed to update config: %w", err))
+	}
+
+	if value == "" {
+		fmt.Printf("Removed %s from %s\n", key, file)
+	} else {
+		fmt.Printf("Set %s to %q in %s\n", key, value, file)
+	}
+}
+
+func listConfig() {
+	config, err := internal.ReadConfig()
+	if err != nil {
+		internal.LogError(err)
+	}
+
+	fmt.Printf("# %s\n", strings.Join(internal.ConfigFiles(), ", "))
+	for _, key := range internal.SortedConfigKeys() {
+		value, ok := config[key]
+		switch {
+		case ok && value != "":
+			fmt.Printf("%s: %s\n", key, value)
+		case listAll:
+			fmt.Printf("# %s: %s\n", key, internal.ConfigKeys[key])
+		}
+	}
+
+	for key := range config {
+		if _, known := internal.ConfigKeys[key]; !known {
+			fmt.Fprintf(os.Stderr, "Warning: unknown config key %q\n", key)
+		}
+	}
+}
+
+func editor() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+func editConfig() {
+	file := writableConfigFile()
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		if err := internal.SetConfigValue(file, "", ""); err != nil {
+			internal.LogError(fmt.Errorf("failed to create config file: %w", err))
+		}
+	}
+
+	command := editor()
+	editorCmd := exec.CommandContext(internal.Context(), command[0], append(command[1:], file)...)
+	editorCmd.Stdin, editorCmd.Stdout, editorCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		internal.LogError(fmt.Errorf("failed to run editor: %w", err))
+	}
+
+	if _, err := internal.ReadConfigFile(file); err != nil {
+		internal.LogError(fmt.Errorf("%w; run 'config edit' again to fix it", err))
+	}
+}
@@ -6,163 +6,67 @@
 package contests
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
+	"kncli/cmd/user"
 	"kncli/internal"
-	u "net/url"
 
 	"github.com/charmbracelet/bubbles/table"
 )
 
 // communication
 func createAnnouncement(contestID, text string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_CREATE_ANNOUNCEMENT, contestID)
-
-	formData := u.Values{
-		"text": {text},
-	}
-
-	body, err := internal.MakePostRequest(url, bytes.NewBufferString(formData.Encode()), internal.RequestFormAuth)
-	if err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	var data internal.KilonovaResponse
-	err = json.Unmarshal(body, &data)
+	message, err := internal.Client().CreateAnnouncement(internal.Context(), contestID, text)
 	if err != nil {
-		internal.LogError(fmt.Errorf("failed to decode response: %w", err))
+		internal.LogError(fmt.Errorf("failed to create an announcement: %w", err))
 		return
 	}
-
-	if data.Status != internal.SUCCESS {
-		fmt.Println("Failed to create an announcement!")
-	} else {
-		fmt.Println(data.Data)
-	}
+	fmt.Println(message)
 }
 
 func updateAnnouncement(contestID, announcementID, text string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_UPDATE_ANNOUNCEMENT, contestID)
-
-	formData := u.Values{
-		"text": {text},
-		"id":   {announcementID},
-	}
-
-	body, err := internal.MakePostRequest(url, bytes.NewBufferString(formData.Encode()), internal.RequestFormAuth)
-	if err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	var data internal.KilonovaResponse
-	err = json.Unmarshal(body, &data)
+	message, err := internal.Client().UpdateAnnouncement(internal.Context(), contestID, announcementID, text)
 	if err != nil {
-		internal.LogError(fmt.Errorf("failed to decode response: %w", err))
+		internal.LogError(fmt.Errorf("failed to update the announcement: %w", err))
 		return
 	}
-
-	if data.Status != internal.SUCCESS {
-		fmt.Println("Failed to update announcement!")
-	} else {
-		fmt.Println(data.Data)
-	}
+	fmt.Println(message)
 }
 
 func deleteAnnouncement(contestID, announcementID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_DELETE_ANNOUNCEMENT, contestID)
-
-	formData := u.Values{
-		"id": {announcementID},
-	}
-
-	body, err := internal.MakePostRequest(url, bytes.NewBufferString(formData.Encode()), internal.RequestFormAuth)
-	if err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	var data internal.KilonovaResponse
-	err = json.Unmarshal(body, &data)
+	message, err := internal.Client().DeleteAnnouncement(internal.Context(), contestID, announcementID)
 	if err != nil {
-		internal.LogError(fmt.Errorf("failed to decode response: %w", err))
+		internal.LogError(fmt.Errorf("failed to delete the announcement: %w", err))
 		return
 	}
-
-	if data.Status != internal.SUCCESS {
-		fmt.Println("Failed to delete announcement!")
-	} else {
-		fmt.Println(data.Data)
-	}
+	fmt.Println(message)
 }
 
 func askQuestion(contestID, text string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_ASK_QUESTION, contestID)
-
-	formData := u.Values{
-		"text": {text},
-	}
-
-	body, err := internal.MakePostRequest(url, bytes.NewBufferString(formData.Encode()), internal.RequestFormAuth)
-	if err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	var data internal.KilonovaResponse
-	err = json.Unmarshal(body, &data)
+	message, err := internal.Client().AskQuestion(internal.Context(), contestID, text)
 	if err != nil {
-		internal.LogError(fmt.Errorf("failed to decode response: %w", err))
+		internal.LogError(fmt.Errorf("failed to ask the question: %w", err))
 		return
 	}
-
-	if data.Status != internal.SUCCESS {
-		fmt.Println("Failed to ask question!")
-	} else {
-		fmt.Println(data.Data)
-	}
+	fmt.Println(message)
 }
 
 func answerQuestion(contestID, questionID, text string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_RESPOND_QUESTION, contestID)
-
-	formData := u.Values{
-		"text":       {text},
-		"questionID": {questionID},
-	}
-
-	body, err := internal.MakePostRequest(url, bytes.NewBufferString(formData.Encode()), internal.RequestFormAuth)
-	if err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	var data internal.KilonovaResponse
-	err = json.Unmarshal(body, &data)
+	message, err := internal.Client().AnswerQuestion(internal.Context(), contestID, questionID, text)
 	if err != nil {
-		internal.LogError(fmt.Errorf("failed to decode response: %w", err))
+		internal.LogError(fmt.Errorf("failed to answer the question: %w", err))
 		return
 	}
-
-	if data.Status != internal.SUCCESS {
-		fmt.Println("Failed to respond to question!")
-	} else {
-		fmt.Println(data.Data)
-	}
+	fmt.Println(message)
 }
 
 func viewAnnouncementsContest(contestID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_ANNOUNCEMENTS, contestID)
-	body, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
+	data, err := internal.Client().Announcements(internal.Context(), contestID)
 	if err != nil {
-		internal.LogError(err)
+		internal.LogError(fmt.Errorf("couldn't retrieve announcements: %w", err))
 		return
 	}
-	var data ContestData
-	if err = json.Unmarshal(body, &data); err != nil {
-		internal.LogError(err)
+
+	if internal.PrintOutput(data) {
 		return
 	}
 
@@ -170,23 +74,18 @@ func viewAnnouncementsContest(contestID string) {
 
 	var Rows []table.Row
 
-	if data.Status != internal.SUCCESS {
-		internal.LogError(fmt.Errorf("couldn't retrieve announcements"))
-		return
-	} else {
-		for _, announ := range data.Data {
-			ok = true
-			parsedTime, err := internal.ParseTime(announ.Time)
-			if err != nil {
-				internal.LogError(err)
-				return
-			}
-			Rows = append(Rows, table.Row{
-				fmt.Sprintf("%d", announ.ID),
-				parsedTime,
-				announ.Text,
-			})
+	for _, announ := range data {
+		ok = true
+		parsedTime, err := internal.ParseTime(announ.Time)
+		if err != nil {
+			internal.LogError(err)
+			return
 		}
+		Rows = append(Rows, table.Row{
+			fmt.Sprintf("%d", announ.ID),
+			parsedTime,
+			announ.Text,
+		})
 	}
 
 	if !ok {
@@ -198,20 +97,18 @@ func viewAnnouncementsContest(contestID string) {
 			{Title: "Text", Width: 57},
 		}
 
-		internal.RenderTable(Columns, Rows, 1)
+		internal.RenderTable(Columns, Rows, nil)
 	}
 }
 
 func viewAllQuestionsContest(contestID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_ALL_QUESTIONS, contestID)
-	body, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
+	data, err := internal.Client().Questions(internal.Context(), contestID, true)
 	if err != nil {
-		internal.LogError(err)
+		internal.LogError(fmt.Errorf("couldn't retrieve questions: %w", err))
 		return
 	}
-	var data ContestQuestions
-	if err = json.Unmarshal(body, &data); err != nil {
-		internal.LogError(err)
+
+	if internal.PrintOutput(data) {
 		return
 	}
 
@@ -219,27 +116,22 @@ func viewAllQuestionsContest(contestID string) {
 
 	var Rows []table.Row
 
-	if data.Status != internal.SUCCESS {
-		internal.LogError(fmt.Errorf("couldn't retrieve questions"))
-		return
-	} else {
-		for _, quest := range data.Data {
-			ok = true
-			formattedTime, err := internal.ParseTime(quest.Time)
-			if err != nil {
-				internal.LogError(err)
-				return
-			}
-
-			Rows = append(Rows, table.Row{
-				formattedTime,
-				fmt.Sprintf("%d", quest.Id),
-				fmt.Sprintf("%d", quest.AuthorID),
-				quest.Text,
-				quest.Response,
-			})
-
+	for _, quest := range data {
+		ok = true
+		formattedTime, err := internal.ParseTime(quest.Time)
+		if err != nil {
+			internal.LogError(err)
+			return
 		}
+
+		Rows = append(Rows, table.Row{
+			formattedTime,
+			fmt.Sprintf("%d", quest.Id),
+			fmt.Sprintf("%d", quest.AuthorID),
+			quest.Text,
+			quest.Response,
+		})
+
 	}
 
 	if !ok {
@@ -253,20 +145,18 @@ func viewAllQuestionsContest(contestID string) {
 			{Title: "Response", Width: 17},
 		}
 
-		internal.RenderTable(Columns, Rows, 1)
+		internal.RenderTable(Columns, Rows, user.OpenProfile(2))
 	}
 }
 
 func viewMyQuestionsContest(contestID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_YOUR_QUESTIONS, contestID)
-	body, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
+	data, err := internal.Client().Questions(internal.Context(), contestID, false)
 	if err != nil {
-		internal.LogError(err)
+		internal.LogError(fmt.Errorf("couldn't retrieve questions: %w", err))
 		return
 	}
-	var data ContestQuestions
-	if err = json.Unmarshal(body, &data); err != nil {
-		internal.LogError(err)
+
+	if internal.PrintOutput(data) {
 		return
 	}
 
@@ -274,11 +164,7 @@ func viewMyQuestionsContest(contestID string) {
 
 	var Rows []table.Row
 
-	if data.Status != internal.SUCCESS {
-		internal.LogError(fmt.Errorf("couldn't retrieve questions"))
-		return
-	}
-	for _, quest := range data.Data {
+	for _, quest := range data {
 		ok = true
 		formattedTime, err := internal.ParseTime(quest.Time)
 		if err != nil {
@@ -306,6 +192,6 @@ func viewMyQuestionsContest(contestID string) {
 		{Title: "Response", Width: 21},
 	}
 
-	internal.RenderTable(Columns, Rows, 1)
+	internal.RenderTable(Columns, Rows, nil)
 
 }
@@ -6,11 +6,10 @@
 package contests
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
+	"kncli/api"
+	problem "kncli/cmd/problems"
 	"kncli/internal"
-	u "net/url"
 	"strconv"
 
 	"github.com/charmbracelet/bubbles/table"
@@ -52,50 +51,6 @@ func init() {
 	modifyInfoContestCmd.AddCommand(modifyPublicLeaderboardContestCmd)
 }
 
-type ContestData struct {
-	Status string `json:"status"`
-	Data   []struct {
-		Text     string `json:"text"`
-		Time     string `json:"created_at"`
-		ID       int    `json:"id"`
-		Name     string `json:"name"`
-		MaxScore int    `json:"max_score"`
-	}
-}
-
-type ContestInfo struct {
-	Status string `json:"status"`
-	Data   struct {
-		StartTime             string      `json:"start_time"`
-		EndTime               string      `json:"end_time"`
-		MaxSubs               int         `json:"max_subs"`
-		Name                  string      `json:"name"`
-		Visible               bool        `json:"visible"`
-		PublicLeaderboard     bool        `json:"public_leaderboard"`
-		ChangeLeadboardFreeze bool        `json:"change_leaderboard_freeze"`
-		IcpcSubmPenalty       json.Number `json:"icpc_submission_penalty"`
-		LeadAdvFilter         bool        `json:"leaderboard_advanced_filter"`
-		LeadStyle             string      `json:"leaderboard_style"`
-		PerUserTime           json.Number `json:"per_user_time"`
-		PublicJoin            bool        `json:"public_join"`
-		QuestionCoolDown      json.Number `json:"question_contest"`
-		RegisterDuringContest bool        `json:"register_during_contest"`
-		SubmissionCooldown    json.Number `json:"submission_cooldown"`
-	}
-}
-
-type ContestQuestions struct {
-	Status string `json:"status"`
-	Data   []struct {
-		Text          string `json:"text"`
-		Time          string `json:"asked_at"`
-		RespondedTIme string `json:"responded_at"`
-		Response      string `json:"response"`
-		Id            int    `json:"id"`
-		AuthorID      int    `json:"author_id"`
-	}
-}
-
 type ContestUpdate struct {
 	ContestID string
 	DataForm  string
@@ -104,66 +59,44 @@ type ContestUpdate struct {
 
 // contest
 func createContest(name, contestType string) {
-	formData := u.Values{
-		"name": {name},
-		"type": {contestType},
-	}
-
-	body, err := internal.MakePostRequest(internal.URL_CONTEST_CREATE, bytes.NewBufferString(formData.Encode()), internal.RequestFormAuth)
-	if err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	var data internal.RawKilonovaResponse
-	err = json.Unmarshal(body, &data)
+	id, err := internal.Client().CreateContest(internal.Context(), name, contestType)
 	if err != nil {
-		internal.LogError(fmt.Errorf("failed to decode response: %w", err))
+		internal.LogError(fmt.Errorf("failed to create a contest: %w", err))
 		return
 	}
-
-	if data.Status != internal.SUCCESS {
-		fmt.Println("Failed to create a contest!")
-		return
-	}
-	fmt.Println("Your contest's ID: #", string(data.Data))
+	fmt.Println("Your contest's ID: #", id)
 }
 
 func registerContest(contestID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_REGISTER, contestID)
-	body, err := internal.PostJSON[internal.KilonovaResponse](url, nil)
+	message, err := internal.Client().RegisterContest(internal.Context(), contestID)
 	if err != nil {
 		internal.LogError(err)
 		return
 	}
-	fmt.Println(body.Data)
+	fmt.Println(message)
 }
 
 func startContest(contestID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_START, contestID)
-	body, err := internal.PostJSON[internal.KilonovaResponse](url, nil)
+	message, err := internal.Client().StartContest(internal.Context(), contestID)
 	if err != nil {
 		internal.LogError(err)
 		return
 	}
-	fmt.Println(body.Data)
+	fmt.Println(message)
 }
 
 func deleteContest(contestID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_DELETE, contestID)
-	body, err := internal.PostJSON[internal.KilonovaResponse](url, nil)
+	message, err := internal.Client().DeleteContest(internal.Context(), contestID)
 	if err != nil {
 		internal.LogError(err)
 		return
 	}
-	fmt.Println(body.Data)
+	fmt.Println(message)
 }
 
 // manage contest
 
 func updateProblems(contestID string, problemsID []string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_UPDATE_PROBLEMS, contestID)
-
 	var problemsIDInt []int
 	for _, s := range problemsID {
 		num, err := strconv.Atoi(s)
@@ -174,47 +107,22 @@ func updateProblems(contestID string, problemsID []string) {
 		problemsIDInt = append(problemsIDInt, num)
 	}
 
-	payload := map[string]interface{}{
-		"list": problemsIDInt,
-	}
-
-	jsonBytes, err := json.Marshal(payload)
-	if err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	body, err := internal.MakePostRequest(url, bytes.NewBuffer(jsonBytes), internal.RequestJSON)
+	message, err := internal.Client().UpdateContestProblems(internal.Context(), contestID, problemsIDInt)
 	if err != nil {
-		internal.LogError(err)
+		internal.LogError(fmt.Errorf("failed to update problems: %w", err))
 		return
 	}
-
-	var data internal.KilonovaResponse
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		internal.LogError(fmt.Errorf("failed to decode response: %w", err))
-		return
-	}
-
-	if data.Status != internal.SUCCESS {
-		fmt.Println("Failed to update problems!")
-	} else {
-		fmt.Println(data.Data)
-	}
+	fmt.Println(message)
 }
 
 func showProblems(contestID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_PROBLEMS, contestID)
-	body, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
-
+	data, err := internal.Client().ContestProblems(internal.Context(), contestID)
 	if err != nil {
-		internal.LogError(err)
+		internal.LogError(fmt.Errorf("couldn't retrieve contest problems: %w", err))
 		return
 	}
-	var data ContestData
-	if err = json.Unmarshal(body, &data); err != nil {
-		internal.LogError(err)
+
+	if internal.PrintOutput(data) {
 		return
 	}
 
@@ -222,18 +130,13 @@ func showProblems(contestID string) {
 
 	var Rows []table.Row
 
-	if data.Status != internal.SUCCESS {
-		internal.LogError(fmt.Errorf("couldn't retrieve contest problems"))
-		return
-	} else {
-		for _, problem := range data.Data {
-			ok = true
-			Rows = append(Rows, table.Row{
-				fmt.Sprintf("%d", problem.ID),
-				problem.Name,
-				fmt.Sprintf("%d", problem.MaxScore),
-			})
-		}
+	for _, problem := range data {
+		ok = true
+		Rows = append(Rows, table.Row{
+			fmt.Sprintf("%d", problem.Id),
+			problem.Name,
+			fmt.Sprintf("%d", problem.MaxScore),
+		})
 	}
 
 	if !ok {
@@ -245,61 +148,44 @@ func showProblems(contestID string) {
 			{Title: "Max Score", Width: 10},
 		}
 
-		internal.RenderTable(Columns, Rows, 1)
+		internal.RenderTable(Columns, Rows, problem.OpenStatement(0, true))
 	}
 
 }
 
-func infoContest(contestID, useCase string) ContestInfo {
-	url := fmt.Sprintf(internal.URL_CONTEST, contestID)
-	body, err := internal.MakeGetRequest(url, nil, internal.RequestInfo)
-
+func infoContest(contestID, useCase string) api.Contest {
+	data, err := internal.Client().GetContest(internal.Context(), contestID)
 	if err != nil {
 		internal.LogError(err)
-		return ContestInfo{}
+		return api.Contest{}
 	}
-	var data ContestInfo
-	if err = json.Unmarshal(body, &data); err != nil {
-		internal.LogError(err)
-		return ContestInfo{}
+	if useCase != "2" && internal.PrintOutput(*data) {
+		return *data
 	}
 	if useCase != "2" {
-		parsedtime1, err := internal.ParseTime(data.Data.StartTime)
+		parsedtime1, err := internal.ParseTime(data.StartTime)
 		if err != nil {
 			internal.LogError(err)
-			return ContestInfo{}
+			return api.Contest{}
 		}
-		parsedtime2, err := internal.ParseTime(data.Data.EndTime)
+		parsedtime2, err := internal.ParseTime(data.EndTime)
 		if err != nil {
 			internal.LogError(err)
-			return ContestInfo{}
+			return api.Contest{}
 		}
 		fmt.Printf("Name: %s\nStart time: %s\nEnd time: %s\nMax submissions per problem: %d\n",
-			data.Data.Name, parsedtime1, parsedtime2, data.Data.MaxSubs)
+			data.Name, parsedtime1, parsedtime2, data.MaxSubs)
 		fmt.Printf("Public leaderboard: %t\nVisibility: %t\nRegistering during contest: %t\n",
-			data.Data.PublicLeaderboard, data.Data.Visible, data.Data.RegisterDuringContest)
+			data.PublicLeaderboard, data.Visible, data.RegisterDuringContest)
 	}
-	return data
+	return *data
 }
 
 func modifyGeneralContest(update ContestUpdate) {
-	url := fmt.Sprintf(internal.URL_CONTEST_UPDATE, update.ContestID)
-
-	formData := u.Values{
-		update.DataForm: {update.Value},
-	}
-
-	body, err := internal.MakePostRequest(url, bytes.NewBufferString(formData.Encode()), internal.RequestFormAuth)
+	message, err := internal.Client().UpdateContest(internal.Context(), update.ContestID, update.DataForm, update.Value)
 	if err != nil {
 		internal.LogError(err)
 		return
 	}
-
-	var data internal.KilonovaResponse
-	if err = json.Unmarshal(body, &data); err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	fmt.Println(data.Data)
+	fmt.Println(message)
 }
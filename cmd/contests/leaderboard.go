@@ -6,33 +6,19 @@
 package contests
 
 import (
-	"encoding/json"
 	"fmt"
+	"kncli/cmd/user"
 	"kncli/internal"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/huh/spinner"
 )
 
-type Leaderboard struct {
-	Status string `json:"status"`
-	Data   struct {
-		ProblemNames map[string]string `json:"problem_names"`
-		Entries      []struct {
-			User struct {
-				ID   int    `json:"id"`
-				Name string `json:"name"`
-			} `json:"user"`
-			Scores map[string]int `json:"scores"`
-			Total  int            `json:"total"`
-		} `json:"entries"`
-	} `json:"data"`
-}
-
 func downloadLeaderboard(contestID string) {
-	resp, err := internal.MakeGetRequest(fmt.Sprintf(internal.URL_CONTEST_ASSETS, contestID), nil, internal.RequestDownloadZip)
+	resp, err := internal.Client().LeaderboardCSV(internal.Context(), contestID)
 	if err != nil {
 		internal.LogError(err)
 		return
@@ -45,37 +31,36 @@ func downloadLeaderboard(contestID string) {
 	}
 
 	downFile := filepath.Join(homedir, "leaderboard_"+contestID+".csv")
-	outFile, err := os.Create(downFile)
-	if err != nil {
-		internal.LogError(fmt.Errorf("failed to create file %q: %w", downFile, err))
-		return
-	}
-	defer outFile.Close()
-
-	if err := os.WriteFile(downFile, resp, 0644); err != nil {
+	if err := internal.WriteFileAtomic(downFile, resp, 0644); err != nil {
 		internal.LogError(fmt.Errorf("failed to write to file %q: %w", downFile, err))
 		return
 	}
 
-	fmt.Printf("Leaderboard to contest #%s saved to %q\n", contestID, downFile)
+	fmt.Fprintf(os.Stderr, "Leaderboard to contest #%s saved to %q\n", contestID, downFile)
 }
 
 func leaderboard(contestID string) {
-	url := fmt.Sprintf(internal.URL_CONTEST_LEADERBOARD, contestID)
-	body, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
+	data, err := internal.Client().Leaderboard(internal.Context(), contestID)
 	if err != nil {
 		internal.LogError(err)
 		return
 	}
-	var data Leaderboard
-	if err = json.Unmarshal(body, &data); err != nil {
-		internal.LogError(err)
+
+	records := any(data)
+	if internal.TabularOutput() {
+		// One line per entry, with a scores.<problem ID> column per problem.
+		records = data.Entries
+	}
+	if internal.PrintOutput(records) {
+		if shouldDownload {
+			downloadLeaderboard(contestID)
+		}
 		return
 	}
 
 	var Rows []table.Row
 
-	for _, entry := range data.Data.Entries {
+	for _, entry := range data.Entries {
 		var scores string
 		for _, score := range entry.Scores {
 			scores += fmt.Sprintf("%d  ", score)
@@ -88,7 +73,7 @@ func leaderboard(contestID string) {
 	}
 
 	var problemNamesTitle string
-	for id, name := range data.Data.ProblemNames {
+	for id, name := range data.ProblemNames {
 		problemNamesTitle += "| #" + id + " " + name + " "
 	}
 
@@ -100,7 +85,11 @@ func leaderboard(contestID string) {
 		{Title: "Total", Width: 5},
 	}
 
-	internal.RenderTable(Columns, Rows, 1)
+	// The first column holds the user's ID followed by their name.
+	openUser := internal.OpenText(func(row table.Row) (string, error) {
+		return user.ProfileText(strings.Fields(row[0])[0])
+	})
+	internal.RenderTable(Columns, Rows, openUser)
 
 	if shouldDownload {
 		action := func() { downloadLeaderboard(contestID) }
@@ -9,16 +9,20 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"github.com/charmbracelet/huh/spinner"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/spf13/cobra"
-	"kncli/cmd/problems"
+	"kncli/api"
 	"kncli/internal"
 	"os"
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -32,11 +36,7 @@ var CreateDBCmd = &cobra.Command{
 	Short: "Creates the problem database. (online)",
 	Args:  cobra.ExactArgs(0),
 	Run: func(cmd *cobra.Command, args []string) {
-		action := func() { CreateDB() }
-		if err := spinner.New().Title("Please wait...").Action(action).Run(); err != nil {
-			internal.LogError(err)
-			return
-		}
+		CreateDB(Jobs(cmd))
 	},
 }
 
@@ -54,42 +54,73 @@ var RefreshDBCmd = &cobra.Command{
 	Short: "Refreshes the problem database. (online)",
 	Args:  cobra.ExactArgs(0),
 	Run: func(cmd *cobra.Command, args []string) {
-		action := func() { refreshDB() }
-		if err := spinner.New().Title("Please wait...").Action(action).Run(); err != nil {
-			internal.LogError(err)
+		refreshDB(Jobs(cmd))
+	},
+}
+
+var MigrateDBCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrades the problem database to the current schema.",
+	Long: `Upgrades the problem database to the current schema.
+Pending migrations are also applied whenever the database is opened. The database file is backed
+up only before migrations marked destructive, which --status points out.`,
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		if migrateStatus {
+			printMigrationStatus()
 			return
 		}
+		migrateDB()
 	},
 }
 
+var jobs int
+
+var migrateStatus = false
+
+// Problems inserted per transaction during a refresh.
+const dbBatchSize = 100
+
 func init() {
+	for _, cmd := range []*cobra.Command{CreateDBCmd, RefreshDBCmd} {
+		cmd.Flags().IntVarP(&jobs, "jobs", "j", internal.DEFAULT_DB_JOBS, "Number of problems downloaded in parallel (env KNCLI_DB_JOBS, config key db_jobs).")
+	}
+
 	DatabaseCmd.AddCommand(CreateDBCmd)
 	DatabaseCmd.AddCommand(DeleteDBCmd)
 	DatabaseCmd.AddCommand(RefreshDBCmd)
+	DatabaseCmd.AddCommand(MigrateDBCmd)
+
+	MigrateDBCmd.Flags().BoolVar(&migrateStatus, "status", false, "Show the schema version and the pending migrations without applying them.")
 }
 
-func CreateDB() {
-	db := internal.DBOpen()
+// Jobs returns the number of download workers for cmd, or the configured
+// default when cmd is nil.
+func Jobs(cmd *cobra.Command) int {
+	flagValue := ""
+	if cmd != nil && cmd.Flags().Changed("jobs") {
+		flagValue = strconv.Itoa(jobs)
+	}
 
-	createProblemTableSQL := `CREATE TABLE IF NOT EXISTS problems (
-id INTEGER PRIMARY KEY,
-name TEXT,
-timelimit FLOAT,
-memorylimit INTEGER,
-sourcesize INTEGER,
-credits TEXT,
-statement TEXT
-);`
-	_, err := db.Exec(createProblemTableSQL)
-	if err != nil {
-		internal.LogError(err)
+	value, ok := internal.Setting(flagValue, internal.ENV_DB_JOBS, internal.CONFIG_DB_JOBS)
+	if !ok {
+		return internal.DEFAULT_DB_JOBS
 	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		internal.LogError(fmt.Errorf("invalid number of jobs %q", value))
+	}
+	return n
+}
 
+func CreateDB(jobs int) {
+	// Opening the database creates the tables.
+	db := internal.DBOpen()
 	internal.DBClose(db)
 
 	println("Database created successfully.")
 
-	refreshDB()
+	refreshDB(jobs)
 
 }
 
@@ -107,71 +138,300 @@ func deleteDB() {
 	println("Database deleted successfully.")
 }
 
-func refreshDB() {
+func migrateDB() {
 	if !internal.DBExists() {
 		fmt.Println(`Database file does not exist. Create it using 'database create'.`)
 		return
 	}
 
-	url := fmt.Sprintf(internal.URL_PROBLEM, "get")
-	data, err := internal.PostJSON[internal.ProblemList](url, nil)
+	db := internal.DBOpenRaw()
+	defer internal.DBClose(db)
+
+	applied, err := internal.MigrateDB(db)
+	if err != nil {
+		internal.LogError(err)
+	}
+
+	if applied == 0 {
+		fmt.Println("Database is up to date.")
+		return
+	}
+	fmt.Printf("Applied %d migration(s), the database is at version %d.\n", applied, len(internal.Migrations))
+}
+
+func printMigrationStatus() {
+	if !internal.DBExists() {
+		fmt.Println(`Database file does not exist. Create it using 'database create'.`)
+		return
+	}
+
+	db := internal.DBOpenRaw()
+	defer internal.DBClose(db)
+
+	version, err := internal.SchemaVersion(db)
+	if err != nil {
+		internal.LogError(err)
+	}
+
+	fmt.Printf("Schema version: %d (latest %d)\n", version, len(internal.Migrations))
+	for i, migration := range internal.Migrations {
+		state := "applied"
+		if i >= version {
+			state = "pending"
+			if migration.Destructive {
+				state += ", backs up the database first"
+			}
+		}
+		fmt.Printf("  %3d  %-40s %s\n", i+1, migration.Description, state)
+	}
+}
+
+func refreshDB(jobs int) {
+	if !internal.DBExists() {
+		fmt.Println(`Database file does not exist. Create it using 'database create'.`)
+		return
+	}
+
+	data, err := internal.Client().ListProblems(internal.Context())
 	if err != nil {
 		internal.LogError(err)
 	}
 
 	db := internal.DBOpen()
+	defer internal.DBClose(db)
 
-	for _, problem := range data.Data {
-		query := `SELECT EXISTS(SELECT 1 FROM problems WHERE id = ?)`
-		var exists bool
-		err := db.QueryRow(query, problem.Id).Scan(&exists)
-		if err != nil {
-			internal.LogError(err)
+	existing, err := problemIDsDB(db)
+	if err != nil {
+		internal.LogError(err)
+	}
+
+	// Problems already in the database are skipped, so an interrupted refresh
+	// resumes where it stopped.
+	var missing []api.Problem
+	for _, problem := range data {
+		if !existing[problem.Id] {
+			missing = append(missing, problem)
 		}
+	}
 
-		if exists {
-			continue
+	if len(missing) > 0 {
+		ctx, cancel := context.WithCancel(internal.Context())
+		defer cancel()
+
+		progress := internal.NewProgress("Fetching problems", len(missing))
+		written, err := storeProblems(ctx, cancel, db, fetchProblems(ctx, missing, jobs), progress)
+		progress.Finish()
+		if err != nil {
+			internal.LogError(fmt.Errorf("refresh stopped after %d new problems (run 'database refresh' again to resume): %w", written, err))
 		}
+	}
+
+	filePath := path.Join(internal.GetConfigDir(), internal.LASTREFRESHDB)
+	if err := internal.WriteFileAtomic(filePath, []byte(time.Now().Format(time.RFC3339)), 0644); err != nil {
+		internal.LogError(err)
+	}
+
+	fmt.Println("Database refreshed successfully.")
+}
+
+type fetchedProblem struct {
+	problem    api.Problem
+	statements []storedStatement
+	err        error
+}
 
-		statement := problems.GetStatementOnline(strconv.Itoa(problem.Id), "RO", 2)
-		if statement == internal.NOLANG {
-			statement = problems.GetStatementOnline(strconv.Itoa(problem.Id), "EN", 2)
+type storedStatement struct {
+	language   string
+	attachment string
+	text       string // base64, as stored
+}
+
+// fetchProblems downloads the statements of problems with a pool of jobs
+// workers. The returned channel is closed once every worker has stopped.
+func fetchProblems(ctx context.Context, problems []api.Problem, jobs int) <-chan fetchedProblem {
+	queue := make(chan api.Problem)
+	results := make(chan fetchedProblem)
+
+	go func() {
+		defer close(queue)
+		for _, problem := range problems {
+			select {
+			case queue <- problem:
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
+
+	var wg sync.WaitGroup
+	for range max(jobs, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for problem := range queue {
+				statements, err := fetchStatements(ctx, strconv.Itoa(problem.Id))
+				select {
+				case results <- fetchedProblem{problem, statements, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
 
-		insertSQL := `INSERT INTO problems (id, name, sourcesize, timelimit, memorylimit, credits, statement)
- VALUES ($1, $2, $3, $4, $5, $6, $7)
-ON CONFLICT (id) DO NOTHING;`
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// fetchStatements returns the statements of a problem in every language it
+// has, encoded for storage.
+func fetchStatements(ctx context.Context, ID string) ([]storedStatement, error) {
+	// The database keeps them, caching them too would only fill the disk.
+	ctx = api.WithoutCache(ctx)
 
-		_, err = db.Exec(insertSQL, problem.Id, problem.Name, problem.SourceSize,
-			problem.Time, problem.MemoryLimit, problem.SourceCredits, statement)
+	var statements []storedStatement
+	for _, language := range api.StatementLanguages {
+		statement, err := internal.Client().Statement(ctx, ID, language)
+
+		var notFound *api.NotFoundError
+		if errors.As(err, &notFound) {
+			continue
+		}
 		if err != nil {
-			internal.LogError(fmt.Errorf("error inserting problem info: %v", err))
+			return nil, fmt.Errorf("error fetching statement of problem %s: %w", ID, err)
 		}
 
+		attachment, err := api.StatementAttachment(language)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := internal.EncodeBase64Text(statement)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, storedStatement{language, attachment, encoded})
 	}
+	return statements, nil
+}
 
-	internal.DBClose(db)
+// storeProblems is the only writer of the database. Problems are inserted in
+// transactions of dbBatchSize, so finished batches survive an interruption.
+// On the first error, or when ctx is cancelled, the workers are stopped and
+// the current batch is rolled back.
+func storeProblems(ctx context.Context, cancel context.CancelFunc, db *sql.DB, results <-chan fetchedProblem, progress *internal.Progress) (int, error) {
+	// Problems stored by older versions get their statements per language,
+	// replacing the single one kept in problems.statement.
+	insertSQL := `INSERT INTO problems (id, name, sourcesize, timelimit, memorylimit, credits, languages)
+ VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id) DO UPDATE SET name = excluded.name, sourcesize = excluded.sourcesize,
+ timelimit = excluded.timelimit, memorylimit = excluded.memorylimit, credits = excluded.credits,
+ languages = excluded.languages, statement = NULL, indexed = 0;`
+	insertStatementSQL := `INSERT INTO statements (problem_id, language, attachment, statement)
+ VALUES ($1, $2, $3, $4)
+ON CONFLICT (problem_id, language) DO UPDATE SET attachment = excluded.attachment, statement = excluded.statement, indexed = 0;`
+
+	var (
+		tx       *sql.Tx
+		written  int
+		batch    int
+		firstErr error
+	)
+
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
 
-	currentTime := time.Now()
-	filePath := path.Join(internal.GetConfigDir(), internal.LASTREFRESHDB)
-	layout := time.RFC3339
+	for result := range results {
+		if firstErr != nil {
+			continue
+		}
+		if result.err != nil {
+			fail(result.err)
+			continue
+		}
 
-	if !internal.FileExists(internal.LASTREFRESHDB) {
-		_, err := os.Create(filePath)
+		if tx == nil {
+			var err error
+			if tx, err = db.Begin(); err != nil {
+				fail(err)
+				continue
+			}
+		}
+
+		problem := result.problem
+		var languages []string
+		for _, statement := range result.statements {
+			languages = append(languages, statement.language)
+		}
+		_, err := tx.Exec(insertSQL, problem.Id, problem.Name, problem.SourceSize,
+			problem.Time, problem.MemoryLimit, problem.SourceCredits, strings.Join(languages, internal.LANGUAGESEPARATOR))
 		if err != nil {
-			internal.LogError(err)
+			fail(fmt.Errorf("error inserting problem info: %v", err))
+			continue
+		}
+		for _, statement := range result.statements {
+			if _, err = tx.Exec(insertStatementSQL, problem.Id, statement.language, statement.attachment, statement.text); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			fail(fmt.Errorf("error inserting statement: %v", err))
+			continue
+		}
+		batch++
+		progress.Increment()
+
+		if batch == dbBatchSize {
+			if err := tx.Commit(); err != nil {
+				fail(err)
+				tx = nil
+				continue
+			}
+			written += batch
+			batch, tx = 0, nil
 		}
 	}
 
-	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		internal.LogError(err)
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+
+	if tx != nil {
+		if firstErr != nil {
+			_ = tx.Rollback()
+		} else if err := tx.Commit(); err != nil {
+			firstErr = err
+		} else {
+			written += batch
+		}
 	}
 
-	_, err = file.WriteString(currentTime.Format(layout))
+	return written, firstErr
+}
+
+// problemIDsDB returns the problems whose statements are stored, leaving out
+// those stored by older versions with a single statement.
+func problemIDsDB(db *sql.DB) (map[int]bool, error) {
+	rows, err := db.Query(`SELECT id FROM problems WHERE languages IS NOT NULL;`)
 	if err != nil {
-		internal.LogError(err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	fmt.Println("Database refreshed successfully.")
+	ids := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
 }
@@ -8,7 +8,6 @@ package problems
 import (
 	"fmt"
 	"kncli/internal"
-	"os"
 
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/spf13/cobra"
@@ -31,13 +30,7 @@ func init() {
 }
 
 func saveToFile(filename string, data []byte) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("could not create file: %w", err)
-	}
-	defer file.Close()
-
-	if _, err := file.Write(data); err != nil {
+	if err := internal.WriteFileAtomic(filename, data, 0644); err != nil {
 		return fmt.Errorf("could not write to file: %w", err)
 	}
 
@@ -45,10 +38,10 @@ func saveToFile(filename string, data []byte) error {
 }
 
 func GetAssets(id string) error {
-	url := fmt.Sprintf(internal.URL_ASSETS, id)
-
 	OutputFile := fmt.Sprintf("%s.zip", id)
-	DataToBeWritten, err := internal.MakeGetRequest(url, nil, internal.RequestDownloadZip)
+
+	fmt.Println("Trying to obtain archive...")
+	DataToBeWritten, err := internal.Client().ProblemArchive(internal.Context(), id)
 	if err != nil {
 		internal.LogError(fmt.Errorf("error making request: %v", err))
 		return err
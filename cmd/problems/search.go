@@ -6,25 +6,37 @@
 package problems
 
 import (
-	"bytes"
-	"encoding/json"
+	"database/sql"
 	"fmt"
+	"kncli/api"
 	"kncli/internal"
+	"os"
 	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
-	"github.com/eiannone/keyboard"
 	"github.com/spf13/cobra"
 )
 
 var onlinesearch = false
+var textsearch = false
+var searchLanguage = ""
 
 var SearchCmd = &cobra.Command{
 	Use:   "search [ID, NAME or all (all problems available)]",
 	Short: "Search for problems by ID or name.",
+	Long:  "Search for problems by ID or name.\n" + textSearchHelp(),
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		if onlinesearch {
+		if textsearch {
+			searchProblemsText(args[0])
+			return
+		}
+
+		onlinesearch = internal.BoolFlagSetting(cmd, "online", internal.ENV_ONLINE, internal.CONFIG_ONLINE)
+		if onlinesearch && (internal.OutputFormat != internal.OUTPUT_TABLE || !internal.UseTUI()) {
+			searchProblemsOnline(args[0])
+		} else if onlinesearch {
 			fmt.Println("Starting network services for online searching ...")
 			searchProblemsOnline(args[0])
 			fmt.Println("Disabling network services for online searching ...")
@@ -34,101 +46,95 @@ var SearchCmd = &cobra.Command{
 	},
 }
 
-func init() {
-	SearchCmd.Flags().BoolVarP(&onlinesearch, "online", "o", false, "Online search for problems. May take longer.")
+// textSearchHelp describes --text as this build runs it: the query syntax
+// and ranking need SQLite's FTS5.
+func textSearchHelp() string {
+	if internal.FTS5 {
+		return `With --text, search the names, sources and statements in the local database instead, best matches
+first. The query can use "phrases", prefix*, AND, OR, NOT and NEAR(...), e.g.
+  kncli search --text '"arbore de intervale" NOT lazy'`
+	}
+	return `With --text, search the names, sources and statements in the local database instead. This build has
+no FTS5 (build kncli with -tags sqlite_fts5 for the full query syntax), so it lists the problems
+containing every word, ranked by how often they occur. "Phrases" and prefix* are searched as plain
+words and OR, NOT and NEAR(...) are rejected, e.g.
+  kncli search --text 'arbore intervale'`
 }
 
-type SearchResponse struct {
-	Status string `json:"status"`
-	Data   struct {
-		Count    int `json:"count"`
-		Problems []struct {
-			Id            int    `json:"id"`
-			Name          string `json:"name"`
-			SourceCredits string `json:"source_credits"`
-			MaxScore      int    `json:"max_score"`
-		}
-	} `json:"data"`
+func init() {
+	SearchCmd.Flags().BoolVarP(&onlinesearch, "online", "o", false, "Online search for problems. May take longer (env KNCLI_ONLINE, config key online).")
+	SearchCmd.Flags().BoolVarP(&textsearch, "text", "t", false, "Full-text search of the statements in the local database.")
+	SearchCmd.Flags().StringVarP(&searchLanguage, "language", "l", "", "Statement language opened with Enter, and searched by --text (RO or EN, config key statement_language).")
+	SearchCmd.MarkFlagsMutuallyExclusive("online", "text")
 }
 
-func fetchProblemsOnline(ProblemName string) ([]table.Row, error) {
+func fetchProblemsOnline(ProblemName string) ([]api.Problem, error) {
 	if ProblemName == "all" {
 		ProblemName = ""
 	}
 
-	SearchData := map[string]interface{}{
-		"name_fuzzy": ProblemName,
-		"offset":     0,
-	}
+	SearchData := api.ProblemFilter{NameFuzzy: ProblemName}
 
-	var Rows []table.Row
+	var Problems []api.Problem
 
-	Data, err := doSearchOnline(SearchData)
+	Data, err := internal.Client().SearchProblems(internal.Context(), SearchData)
 	if err != nil {
 		return nil, err
 	}
 
-	NumberOfPages := (Data.Data.Count + 49) / 50
+	NumberOfPages := (Data.Count + 49) / 50
 
 	for Page := 0; Page < NumberOfPages; Page++ {
-		SearchData["offset"] = Page * 50
+		SearchData.Offset = Page * 50
 
-		PageData, err := doSearchOnline(SearchData)
+		PageData, err := internal.Client().SearchProblems(internal.Context(), SearchData)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, Problem := range PageData.Data.Problems {
-			if Problem.MaxScore == -1 {
-				Problem.MaxScore = 0
-			}
-			if Problem.SourceCredits == "" {
-				Problem.SourceCredits = "-"
-			}
-			Rows = append(Rows, table.Row{
-				strconv.Itoa(Problem.Id),
-				Problem.Name,
-				Problem.SourceCredits,
-				strconv.Itoa(Problem.MaxScore),
-			})
-		}
+		Problems = append(Problems, PageData.Problems...)
 	}
 
-	return Rows, nil
+	return Problems, nil
 }
 
-func doSearchOnline(searchData map[string]interface{}) (*SearchResponse, error) {
-	payload, err := json.Marshal(searchData)
-	if err != nil {
-		return nil, fmt.Errorf("JSON marshal error: %w", err)
-	}
-
-	body, err := internal.MakePostRequest(internal.URL_SEARCH, bytes.NewBuffer(payload), internal.RequestJSON)
-	if err != nil {
-		return nil, fmt.Errorf("POST request failed: %w", err)
-	}
-
-	var res SearchResponse
-	if err := json.Unmarshal(body, &res); err != nil {
-		return nil, fmt.Errorf("JSON unmarshal error: %w", err)
-	}
-
-	return &res, nil
+func problemRows(Problems []api.Problem, offline bool) []table.Row {
+	var Rows []table.Row
+	for _, Problem := range Problems {
+		MaxScore := strconv.Itoa(max(Problem.MaxScore, 0))
+		if offline {
+			MaxScore = "Offline"
+		}
+		if Problem.SourceCredits == "" {
+			Problem.SourceCredits = "-"
+		}
+		Rows = append(Rows, table.Row{
+			strconv.Itoa(Problem.Id),
+			Problem.Name,
+			Problem.SourceCredits,
+			MaxScore,
+		})
+	}
+	return Rows
 }
 
 func searchProblemsOnline(ProblemName string) {
-	Rows, err := fetchProblemsOnline(ProblemName)
+	Problems, err := fetchProblemsOnline(ProblemName)
 	if err != nil {
 		internal.LogError(fmt.Errorf("error fetching problems: %v", err))
 		return
 	}
 
-	if len(Rows) == 0 {
+	if internal.PrintOutput(Problems) {
+		return
+	}
+
+	if len(Problems) == 0 {
 		fmt.Println("No problems found.")
 		return
 	}
 
-	internal.GlobalRows = Rows
+	Rows := problemRows(Problems, false)
 
 	Columns := []table.Column{
 		{Title: "ID", Width: 5},
@@ -137,43 +143,7 @@ func searchProblemsOnline(ProblemName string) {
 		{Title: "Max Score", Width: 10},
 	}
 
-	internal.RenderTable(Columns, Rows, 2)
-
-	if internal.ChosenProblem != "" {
-		chooseLanguageAndShowStatement()
-	}
-}
-
-func chooseLanguageAndShowStatement() {
-	Online = true
-	fmt.Print("\nDo you wish to see the statement in RO(r) or EN(e): ")
-
-	if err := keyboard.Open(); err != nil {
-		internal.LogError(err)
-		return
-	}
-	defer keyboard.Close()
-
-	for {
-		key, _, err := keyboard.GetSingleKey()
-		if err != nil {
-			internal.LogError(fmt.Errorf("key read error: %w", err))
-			return
-		}
-
-		switch key {
-		case 'r', 'R':
-			_, _ = PrintStatement(internal.ChosenProblem, "RO", 1)
-			return
-		case 'e', 'E':
-			_, _ = PrintStatement(internal.ChosenProblem, "EN", 1)
-			return
-		case rune(keyboard.KeyEsc):
-			return
-		default:
-			fmt.Print("Please press 'r' for RO or 'e' for EN (ESC to cancel): ")
-		}
-	}
+	internal.RenderTable(Columns, Rows, OpenStatement(0, true))
 }
 
 func searchProblemsLocal(ProblemName string) {
@@ -182,7 +152,7 @@ func searchProblemsLocal(ProblemName string) {
 	}
 
 	if internal.RefreshOrNotDB() {
-		defer fmt.Println("Warning: You should refresh the database using 'database refresh' to get more problems.")
+		defer fmt.Fprintln(os.Stderr, "Warning: You should refresh the database using 'database refresh' to get more problems.")
 	}
 
 	if ProblemName == "all" {
@@ -192,16 +162,16 @@ func searchProblemsLocal(ProblemName string) {
 	db := internal.DBOpen()
 	defer internal.DBClose(db)
 
-	var Rows []table.Row
+	var Problems []localProblem
 
 	var pattern, query string
 
 	pattern = "%" + ProblemName + "%"
 
 	if _, err := internal.ValidateInt(ProblemName); err == nil {
-		query = "SELECT id, name, credits\nFROM problems\nWHERE CAST(id AS TEXT) LIKE ?"
+		query = "SELECT id, name, timelimit, memorylimit, sourcesize, credits, languages\nFROM problems\nWHERE CAST(id AS TEXT) LIKE ?"
 	} else {
-		query = "SELECT id, name, credits\nFROM problems\nWHERE name LIKE ?;"
+		query = "SELECT id, name, timelimit, memorylimit, sourcesize, credits, languages\nFROM problems\nWHERE name LIKE ?;"
 	}
 
 	rows, err := db.Query(query, pattern)
@@ -212,36 +182,65 @@ func searchProblemsLocal(ProblemName string) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var id int
-		var name, credits string
-		if err := rows.Scan(&id, &name, &credits); err != nil {
+		Problem := localProblem{Problem: api.Problem{MaxScore: localMaxScore}}
+		var languages sql.NullString
+		if err := rows.Scan(&Problem.Id, &Problem.Name, &Problem.Time, &Problem.MemoryLimit, &Problem.SourceSize, &Problem.SourceCredits, &languages); err != nil {
 			internal.LogError(err)
 			continue
 		}
+		Problem.Languages = storedLanguages(languages)
 
-		Rows = append(Rows, table.Row{strconv.Itoa(id), name, credits, "Offline"})
+		Problems = append(Problems, Problem)
 	}
 
 	if err := rows.Err(); err != nil {
 		internal.LogError(err)
 	}
 
+	if internal.PrintOutput(Problems) {
+		return
+	}
+
+	var Rows []table.Row
+	for _, Problem := range Problems {
+		Row := problemRows([]api.Problem{Problem.Problem}, true)[0]
+		Rows = append(Rows, append(Row, languagesCell(Problem.Languages)))
+	}
+
 	Columns := []table.Column{
 		{Title: "ID", Width: 5},
 		{Title: "Name", Width: 20},
 		{Title: "Source", Width: 40},
 		{Title: "Max Score", Width: 10},
+		{Title: "Languages", Width: 9},
 	}
 
-	internal.GlobalRows = Rows
+	internal.RenderTable(Columns, Rows, OpenStatement(0, false))
+}
+
+// localProblem is a problem in the database with the languages of its stored
+// statements: nil when they're unknown, for problems stored by older versions.
+type localProblem struct {
+	api.Problem
+	Languages []string `json:"languages"`
+}
 
-	internal.RenderTable(Columns, Rows, 2)
+func storedLanguages(languages sql.NullString) []string {
+	if !languages.Valid {
+		return nil
+	}
+	if languages.String == "" {
+		return []string{}
+	}
+	return strings.Split(languages.String, internal.LANGUAGESEPARATOR)
+}
 
-	if internal.ChosenProblem != "" {
-		if onlinesearch {
-			chooseLanguageAndShowStatement()
-			return
-		}
-		_, _ = PrintStatement(internal.ChosenProblem, "null", 1)
+func languagesCell(languages []string) string {
+	if languages == nil {
+		return "?"
+	}
+	if len(languages) == 0 {
+		return "-"
 	}
+	return strings.Join(languages, " ")
 }
@@ -7,16 +7,18 @@ package problems
 
 import (
 	"bytes"
-	"encoding/json"
+	"database/sql"
 	"errors"
 	"fmt"
+	"kncli/api"
 	"kncli/internal"
+	"os"
 	"regexp"
 	"strings"
 
 	"text/template"
 
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/glamour"
 	"github.com/spf13/cobra"
 )
@@ -24,22 +26,51 @@ import (
 var Online = false
 
 var PrintStatementCmd = &cobra.Command{
-	Use:   "statement [ID] [RO or EN (required for online)]",
+	Use:   "statement [ID] [RO or EN (optional, config key statement_language)]",
 	Short: "Print problem statement in chosen language.",
 	Args:  cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
+		Online = internal.BoolFlagSetting(cmd, "online", internal.ENV_ONLINE, internal.CONFIG_ONLINE)
+
+		language := ""
 		if len(args) > 1 {
+			language = args[1]
+		}
+		language = StatementLanguage(language)
+
+		if Online && internal.UseTUI() {
 			fmt.Println("Starting network services for online searching ...")
-			_, _ = PrintStatement(args[0], args[1], 1)
+			_, _ = PrintStatement(args[0], language, 1)
 			fmt.Println("Disabling network services for online searching ...")
 		} else {
-			_, _ = PrintStatement(args[0], "NO_LANG_CHOSEN", 1)
+			_, _ = PrintStatement(args[0], language, 1)
 		}
 	},
 }
 
+var PrintProblemInfoCmd = &cobra.Command{
+	Use:   "problem [ID]",
+	Short: "Print problem details: limits and source.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		Online = internal.BoolFlagSetting(cmd, "online", internal.ENV_ONLINE, internal.CONFIG_ONLINE)
+		printProblemInfo(args[0])
+	},
+}
+
 func init() {
-	PrintStatementCmd.Flags().BoolVarP(&Online, "online", "o", false, "Get problem statement online.")
+	PrintStatementCmd.Flags().BoolVarP(&Online, "online", "o", false, "Get problem statement online (env KNCLI_ONLINE, config key online).")
+	PrintProblemInfoCmd.Flags().BoolVarP(&Online, "online", "o", false, "Get problem details online (env KNCLI_ONLINE, config key online).")
+}
+
+// StatementLanguage returns the statement language given as argument, from
+// KNCLI_STATEMENT_LANGUAGE or the config, RO by default.
+func StatementLanguage(argument string) string {
+	language, ok := internal.Setting(argument, internal.ENV_STATEMENT_LANGUAGE, internal.CONFIG_STATEMENT_LANGUAGE)
+	if !ok {
+		return internal.DEFAULT_STATEMENT_LANGUAGE
+	}
+	return strings.ToUpper(language)
 }
 
 func formatText(DecodedText string) string {
@@ -59,52 +90,77 @@ func formatText(DecodedText string) string {
 	return DecodedText
 }
 
-type Statement struct {
-	Status string `json:"status"`
-	Data   struct {
-		Data string `json:"data"`
-	} `json:"data"`
-}
-
 // Problem Details
 
-func GetProblemInfoStructOnline(ID string) (internal.ProblemInfo, error) {
-	url := fmt.Sprintf(internal.URL_PROBLEM, ID)
-	ResponseBody, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
+func GetProblemInfoStructOnline(ID string) (api.Problem, error) {
+	problem, err := internal.Client().GetProblem(internal.Context(), ID)
 	if err != nil {
-		internal.LogError(err)
+		return api.Problem{}, err
 	}
 
-	var ProblemInfo internal.ProblemInfo
-	if err := json.Unmarshal(ResponseBody, &ProblemInfo); err != nil {
-		var res internal.RawKilonovaResponse
-		if err := json.Unmarshal(ResponseBody, &res); err != nil {
-			internal.LogError(err)
-		}
-		return internal.ProblemInfo{}, fmt.Errorf("no")
-	}
-
-	return ProblemInfo, nil
+	return *problem, nil
 }
 
-func GetProblemInfoStructLocal(ID string) (internal.ProblemInfo, error) {
+// localMaxScore is the MaxScore of problems read from the database, which
+// doesn't know the user's scores: -1 is what the API returns for none.
+const localMaxScore = -1
+
+func GetProblemInfoStructLocal(ID string) (api.Problem, error) {
 	db := internal.DBOpen()
 	defer db.Close()
 
 	query := "SELECT id, name, timelimit, memorylimit, sourcesize, credits FROM problems\nWHERE CAST(id AS TEXT) LIKE $1;"
 
-	var data internal.Problem
+	var data api.Problem
 	_ = db.QueryRow(query, ID).Scan(&data.Id, &data.Name, &data.Time, &data.MemoryLimit, &data.SourceSize, &data.SourceCredits)
 
-	return internal.ProblemInfo{Data: data}, nil
+	return data, nil
 }
 
-func GetProblemInfoText(ID string) string {
-	var ProblemInfo internal.ProblemInfo
+func printProblemInfo(ID string) {
+	var ProblemInfo api.Problem
+	var err error
 	if Online {
-		ProblemInfo, _ = GetProblemInfoStructOnline(ID)
+		ProblemInfo, err = GetProblemInfoStructOnline(ID)
+	} else {
+		if !internal.DBExists() {
+			internal.LogError(fmt.Errorf("problem database doesn't exist! Signin or run 'database create' "))
+		}
+		if !internal.ProblemExistsDB(ID) {
+			internal.LogError(fmt.Errorf("no problem with this ID found in the database"))
+		}
+		ProblemInfo, err = GetProblemInfoStructLocal(ID)
+		ProblemInfo.MaxScore = localMaxScore
+	}
+	if err != nil {
+		internal.LogError(err)
+	}
+
+	if internal.PrintOutput(ProblemInfo) {
+		return
+	}
+	fmt.Println(GetProblemInfoText(ID))
+}
+
+func GetProblemInfoText(ID string) string {
+	text, err := problemInfoText(ID, Online)
+	if err != nil {
+		internal.LogError(err)
+		return ""
+	}
+	return text
+}
+
+func problemInfoText(ID string, online bool) (string, error) {
+	var ProblemInfo api.Problem
+	var err error
+	if online {
+		ProblemInfo, err = GetProblemInfoStructOnline(ID)
 	} else {
-		ProblemInfo, _ = GetProblemInfoStructLocal(ID)
+		ProblemInfo, err = GetProblemInfoStructLocal(ID)
+	}
+	if err != nil {
+		return "", err
 	}
 
 	data := struct {
@@ -115,12 +171,12 @@ func GetProblemInfoText(ID string) string {
 		SourceSize  int
 		Credits     string
 	}{
-		Name:        ProblemInfo.Data.Name,
+		Name:        ProblemInfo.Name,
 		ID:          ID,
-		TimeLimit:   ProblemInfo.Data.Time,
-		MemoryLimit: ProblemInfo.Data.MemoryLimit,
-		SourceSize:  ProblemInfo.Data.SourceSize,
-		Credits:     ProblemInfo.Data.SourceCredits,
+		TimeLimit:   ProblemInfo.Time,
+		MemoryLimit: ProblemInfo.MemoryLimit,
+		SourceSize:  ProblemInfo.SourceSize,
+		Credits:     ProblemInfo.SourceCredits,
 	}
 
 	if data.Credits == "" {
@@ -129,71 +185,67 @@ func GetProblemInfoText(ID string) string {
 
 	TemplateCompleted, err := template.New("ProblemInfo").Parse(internal.TemplatePattern)
 	if err != nil {
-		internal.LogError(err)
-		return ""
+		return "", err
 	}
 
 	var Buffer bytes.Buffer
 	if err := TemplateCompleted.Execute(&Buffer, data); err != nil {
-		internal.LogError(err)
-		return ""
+		return "", err
 	}
 
-	return Buffer.String()
+	return Buffer.String(), nil
 }
 
 // Problem statement
 
-func getStatementURL(id, lang string) (string, error) {
-	switch strings.ToUpper(lang) {
-	case "RO":
-		return fmt.Sprintf(internal.URL_STATEMENT, id, internal.STAT_FILENAME_RO), nil
-	case "EN":
-		return fmt.Sprintf(internal.URL_STATEMENT, id, internal.STAT_FILENAME_EN), nil
-	default:
-		return "", fmt.Errorf("invalid language chosen: %q. Must be 'RO' or 'EN'", lang)
-	}
-}
-
 func GetStatementOnline(ID, language string, useCase int) string {
-	url, err := getStatementURL(ID, language)
-	if err != nil {
-		internal.LogError(fmt.Errorf("error fetching URL: %w", err))
-	}
+	statement, err := internal.Client().Statement(internal.Context(), ID, language)
 
-	var ResponseBody []byte
-	if useCase == 1 {
-		ResponseBody, err = internal.MakeGetRequest(url, nil, internal.RequestNone)
-	} else if useCase == 2 {
-		ResponseBody, err = internal.MakeGetRequest(url, nil, internal.RequestDatabase)
+	var notFound *api.NotFoundError
+	if errors.As(err, &notFound) {
+		return internal.NOLANG
 	}
 	if err != nil {
 		internal.LogError(fmt.Errorf("error fetching statement: %w", err))
 	}
 
-	if strings.Contains(string(ResponseBody), "notfound") {
-		return internal.NOLANG
-	}
-
-	var Statement Statement
-	if err := json.Unmarshal(ResponseBody, &Statement); err != nil {
-		internal.LogError(fmt.Errorf("failed to parse statement: %w", err))
-	}
-
-	return Statement.Data.Data
+	return statement
 }
 
-func GetStatementLocal(ID string) string {
+// legacyStatementWarning is returned for problems stored by older versions,
+// with a single statement in either language.
+const legacyStatementWarning = "Only one statement of this problem is stored, run 'database refresh' to get every language."
+
+// GetStatementLocal returns the stored statement in language, or NOLANG, and
+// a warning for the caller to show when it may be in another language.
+func GetStatementLocal(ID, language string) (statement, warning string, err error) {
 
 	db := internal.DBOpen()
 	defer db.Close()
 
-	query := "SELECT statement FROM problems\nWHERE CAST(id AS TEXT) LIKE $1;"
+	var languages, legacy sql.NullString
+	query := "SELECT languages, statement FROM problems\nWHERE CAST(id AS TEXT) LIKE $1;"
+	_ = db.QueryRow(query, ID).Scan(&languages, &legacy)
 
-	var statement string
-	_ = db.QueryRow(query, ID).Scan(&statement)
+	if languages.Valid {
+		query = "SELECT statement FROM statements\nWHERE CAST(problem_id AS TEXT) LIKE $1 AND language = $2;"
+		if err := db.QueryRow(query, ID, strings.ToUpper(language)).Scan(&statement); err != nil {
+			return internal.NOLANG, "", nil
+		}
+	} else {
+		statement, warning = legacy.String, legacyStatementWarning
+	}
 
-	return statement
+	if statement == "" || statement == internal.NOLANG {
+		return internal.NOLANG, warning, nil
+	}
+
+	text, err := internal.DecodeBase64Text(statement)
+	if err != nil {
+		return "", warning, err
+	}
+
+	return text, warning, nil
 }
 
 func PrintStatement(ID, language string, useCase int) (string, error) { // 1 - Print, 2 - Return text
@@ -206,17 +258,29 @@ func PrintStatement(ID, language string, useCase int) (string, error) { // 1 - P
 		}
 
 		if internal.RefreshOrNotDB() {
-			defer fmt.Println("Warning: You should refresh the database using 'database refresh' to get more problems.")
+			defer fmt.Fprintln(os.Stderr, "Warning: You should refresh the database using 'database refresh' to get more problems.")
 		}
 		if !internal.ProblemExistsDB(ID) {
 			fmt.Println("No problem with this ID found in the database.")
 			return "", nil
 		}
 
-		statement = GetStatementLocal(ID)
+		var warning string
+		var err error
+		statement, warning, err = GetStatementLocal(ID, language)
+		if warning != "" {
+			fmt.Fprintln(os.Stderr, "Warning: "+warning)
+		}
+		if err != nil {
+			internal.LogError(err)
+		}
 	}
 
 	if statement == internal.NOLANG {
+		if useCase == 2 {
+			// Let the caller fall back to the other language.
+			return "", errors.New(internal.NOLANG)
+		}
 		if language == "RO" {
 			internal.LogError(fmt.Errorf("statement not available in Romanian. Try again in English"))
 		} else if language == "EN" {
@@ -226,21 +290,18 @@ func PrintStatement(ID, language string, useCase int) (string, error) { // 1 - P
 		}
 	}
 
-	text, err := internal.DecodeBase64Text(statement)
-	if err != nil {
-		if useCase == 2 {
-			internal.LogError(errors.New(internal.NOLANG))
-		}
-		internal.LogError(fmt.Errorf("failed to decode base64 text: %w", err))
-	}
-
-	DecodedText := formatText(text)
+	DecodedText := formatText(statement)
 
 	if useCase == 2 {
 		return DecodedText, nil
 	}
 
-	Rendered, err := renderStatement(ID, DecodedText)
+	ProblemInfoText := GetProblemInfoText(ID)
+	if ProblemInfoText == "" {
+		return "error", errors.New("failed to retrieve problem information")
+	}
+
+	Rendered, err := renderStatement(ProblemInfoText, DecodedText)
 	if err != nil {
 		return "error", fmt.Errorf("failed to render statement: %w", err)
 	}
@@ -252,15 +313,68 @@ func PrintStatement(ID, language string, useCase int) (string, error) { // 1 - P
 	return DecodedText, nil
 }
 
+// StatementText returns the details and statement of a problem rendered for
+// the statement viewer, with the errors returned instead of logged and the
+// warnings shown above the details.
+func StatementText(ID, language string, online bool) (string, error) {
+	var statement, warning string
+	if online {
+		var err error
+		statement, err = internal.Client().Statement(internal.Context(), ID, language)
+
+		var notFound *api.NotFoundError
+		if errors.As(err, &notFound) {
+			statement = internal.NOLANG
+		} else if err != nil {
+			return "", fmt.Errorf("error fetching statement: %w", err)
+		}
+	} else {
+		if !internal.DBExists() || !internal.ProblemExistsDB(ID) {
+			return "", fmt.Errorf("no problem with ID %s found in the database", ID)
+		}
+		var err error
+		if statement, warning, err = GetStatementLocal(ID, language); err != nil {
+			return "", err
+		}
+	}
+
+	if statement == internal.NOLANG {
+		return "", fmt.Errorf("statement of problem #%s not available in %s", ID, language)
+	}
+
+	ProblemInfoText, err := problemInfoText(ID, online)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve problem information: %w", err)
+	}
+	if warning != "" {
+		ProblemInfoText = "> **Warning:** " + warning + "\n\n" + ProblemInfoText
+	}
+
+	return renderStatement(ProblemInfoText, formatText(statement))
+}
+
+// OpenStatement opens the statement of the problem whose ID is in the given
+// column, in the language given to search or set with statement_language.
+func OpenStatement(column int, online bool) internal.RowOpener {
+	return internal.OpenText(func(row table.Row) (string, error) {
+		return StatementText(row[column], StatementLanguage(searchLanguage), online)
+	})
+}
+
 // Others
 
-func renderStatement(ID, DecodedText string) (string, error) {
-	ProblemInfoText := GetProblemInfoText(ID)
-	if ProblemInfoText == "" {
-		return "", errors.New("failed to retrieve problem information")
+func renderStatement(ProblemInfoText, DecodedText string) (string, error) {
+	options := []glamour.TermRendererOption{glamour.WithStandardStyle("dark")}
+	if !internal.UseTUI() {
+		// Printed as is, wrapped at the terminal width; without colours when redirected to a file.
+		style := "dark"
+		if !internal.IsTerminal(os.Stdout) {
+			style = "notty"
+		}
+		options = []glamour.TermRendererOption{glamour.WithStandardStyle(style), glamour.WithWordWrap(internal.TerminalWidth())}
 	}
 
-	Renderer, err := glamour.NewTermRenderer(glamour.WithStandardStyle("dark"))
+	Renderer, err := glamour.NewTermRenderer(options...)
 	if err != nil {
 		return "", fmt.Errorf("failed to create renderer: %w", err)
 	}
@@ -274,9 +388,16 @@ func renderStatement(ID, DecodedText string) (string, error) {
 }
 
 func runTUI(rendered string) error {
-	p := tea.NewProgram(internal.NewTextModel(rendered))
-	if _, err := p.Run(); err != nil {
-		return fmt.Errorf("failed to run TUI program: %w", err)
+	if !internal.UseTUI() {
+		// glamour pads every line to the wrap width.
+		lines := strings.Split(rendered, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " ")
+		}
+		fmt.Print(strings.Join(lines, "\n"))
+		return nil
 	}
+
+	internal.RunScreen(internal.NewTextModel(rendered))
 	return nil
 }
@@ -0,0 +1,282 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package problems
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kncli/api"
+	"kncli/internal"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// search --text looks for words in the names, credits and statements of the
+// problems in the local database, best matches first. Each statement is a
+// separate result, in the language given with --language or in all of them.
+
+const (
+	snippetOpen   = "«"
+	snippetClose  = "»"
+	snippetTokens = 12 // words of context in a snippet
+)
+
+type textMatch struct {
+	Id            int    `json:"id"`
+	Name          string `json:"name"`
+	SourceCredits string `json:"source_credits"`
+	// Language of the matched statement, empty for a match in the name or
+	// credits of a problem without statements per language.
+	Language  string   `json:"language"`
+	Languages []string `json:"languages"`
+	Snippet   string   `json:"snippet"`
+	Rank      float64  `json:"rank"`
+}
+
+func searchProblemsText(query string) {
+	if !internal.DBExists() {
+		internal.LogError(fmt.Errorf("problem database doesn't exist! Signin or run 'database create' "))
+	}
+
+	if internal.RefreshOrNotDB() {
+		defer fmt.Fprintln(os.Stderr, "Warning: You should refresh the database using 'database refresh' to get more problems.")
+	}
+
+	language := strings.ToUpper(searchLanguage)
+	if language != "" {
+		if _, err := api.StatementAttachment(language); err != nil {
+			internal.LogError(err)
+		}
+	}
+
+	db := internal.DBOpen()
+	defer internal.DBClose(db)
+
+	var matches []textMatch
+	err := internal.SyncSearchIndex(db)
+	if err == nil {
+		matches, err = searchFTS(db, query, language)
+	}
+	if errors.Is(err, internal.ErrNoFTS5) {
+		fmt.Fprintf(os.Stderr, "Warning: %v; falling back to a slower search without query syntax.\n", err)
+		matches, err = searchPlain(db, query, language)
+	}
+	if err != nil {
+		internal.LogError(err)
+		return
+	}
+
+	if internal.PrintOutput(matches) {
+		return
+	}
+
+	if len(matches) == 0 {
+		fmt.Println("No problems found.")
+		return
+	}
+
+	var Rows []table.Row
+	for _, match := range matches {
+		if match.SourceCredits == "" {
+			match.SourceCredits = "-"
+		}
+		if match.Language == "" {
+			match.Language = "-"
+		}
+		Rows = append(Rows, table.Row{
+			strconv.Itoa(match.Id),
+			match.Name,
+			match.SourceCredits,
+			match.Language,
+			languagesCell(match.Languages),
+			match.Snippet,
+		})
+	}
+
+	Columns := []table.Column{
+		{Title: "ID", Width: 5},
+		{Title: "Name", Width: 20},
+		{Title: "Source", Width: 20},
+		{Title: "Lang", Width: 4},
+		{Title: "Languages", Width: 9},
+		{Title: "Match", Width: 60},
+	}
+
+	// Enter opens the statement that matched.
+	open := internal.OpenText(func(row table.Row) (string, error) {
+		language := row[3]
+		if language == "-" {
+			language = StatementLanguage(searchLanguage)
+		}
+		return StatementText(row[0], language, false)
+	})
+	internal.RenderTable(Columns, Rows, open)
+}
+
+// searchFTS runs an FTS5 query: words, "phrases", prefix*, AND, OR, NOT and
+// NEAR(...). Lower ranks are better matches.
+func searchFTS(db *sql.DB, query, language string) ([]textMatch, error) {
+	rows, err := db.Query(`SELECT statements_fts.problem_id, statements_fts.name, statements_fts.credits,
+statements_fts.language, problems.languages, snippet(statements_fts, -1, ?, ?, '…', ?), rank
+FROM statements_fts JOIN problems ON problems.id = statements_fts.problem_id
+WHERE statements_fts MATCH ? AND (? = '' OR statements_fts.language = ?)
+ORDER BY rank;`, snippetOpen, snippetClose, snippetTokens, query, language, language)
+	if err != nil {
+		return nil, queryError(query, err)
+	}
+	defer rows.Close()
+
+	var matches []textMatch
+	for rows.Next() {
+		var match textMatch
+		var languages sql.NullString
+		if err := rows.Scan(&match.Id, &match.Name, &match.SourceCredits, &match.Language, &languages, &match.Snippet, &match.Rank); err != nil {
+			return nil, err
+		}
+		match.Languages = storedLanguages(languages)
+		match.Snippet = strings.Join(strings.Fields(match.Snippet), " ")
+		matches = append(matches, match)
+	}
+	return matches, queryError(query, rows.Err())
+}
+
+// queryError explains syntax errors, which SQLite may only report while
+// reading the results. "a-b" is read as column a, without column b.
+func queryError(query string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if message := err.Error(); strings.Contains(message, "fts5: syntax error") || strings.Contains(message, "no such column") {
+		return fmt.Errorf("invalid search query %q (%v); quote words containing punctuation", query, err)
+	}
+	return err
+}
+
+// searchPlain is the fallback without FTS5: it keeps the problems containing
+// every word of the query, ranked by how often the words occur.
+func searchPlain(db *sql.DB, query, language string) ([]textMatch, error) {
+	words, err := plainWords(query)
+	if err != nil || len(words) == 0 {
+		return nil, err
+	}
+
+	// The same rows as the full-text index: one per stored statement, and one
+	// for each problem without statements per language.
+	rows, err := db.Query(`SELECT p.id, p.name, p.credits, s.language, p.languages, s.statement
+FROM statements s JOIN problems p ON p.id = s.problem_id
+WHERE ? = '' OR s.language = ?
+UNION ALL
+SELECT id, name, credits, '', languages, statement
+FROM problems
+WHERE (languages IS NULL OR languages = '') AND ? = '';`, language, language, language)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []textMatch
+	for rows.Next() {
+		var match textMatch
+		var credits, languages, statement sql.NullString
+		if err := rows.Scan(&match.Id, &match.Name, &credits, &match.Language, &languages, &statement); err != nil {
+			return nil, err
+		}
+		match.SourceCredits = credits.String
+		match.Languages = storedLanguages(languages)
+
+		text := strings.Join(strings.Fields(match.Name+" "+credits.String+" "+internal.IndexedStatement(statement.String)), " ")
+		folded := foldText(text)
+
+		found := 0
+		for _, word := range words {
+			count := strings.Count(folded, word)
+			if count == 0 {
+				found = -1
+				break
+			}
+			found += count
+		}
+		if found <= 0 {
+			continue
+		}
+
+		// Negative like FTS5's ranks, so that lower is better in both.
+		match.Rank = -float64(found)
+		match.Snippet = plainSnippet(text, folded, words[0])
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Rank < matches[j].Rank })
+	return matches, nil
+}
+
+// plainWords splits a query for searchPlain. AND is what it does anyway, so it
+// is dropped, but the other operators can't be honoured and are rejected
+// rather than searched for as words.
+func plainWords(query string) ([]string, error) {
+	var words []string
+	for _, word := range strings.Fields(query) {
+		switch {
+		case word == "AND":
+			continue
+		case word == "OR", word == "NOT":
+			return nil, fmt.Errorf("%s in search queries needs FTS5: %w", word, internal.ErrNoFTS5)
+		case strings.HasPrefix(word, "NEAR("):
+			return nil, fmt.Errorf("NEAR in search queries needs FTS5: %w", internal.ErrNoFTS5)
+		}
+		if word = strings.Trim(foldText(word), `"*()`); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words, nil
+}
+
+// plainSnippet returns the words around the first occurrence of word.
+// foldText keeps one rune per rune, so positions in folded match text.
+func plainSnippet(text, folded, word string) string {
+	runes := []rune(text)
+	start := len([]rune(folded[:strings.Index(folded, word)]))
+	end := start + len([]rune(word))
+	if end > len(runes) {
+		return ""
+	}
+
+	from, to := start, end
+	for words := 0; from > 0 && words < snippetTokens/2; from-- {
+		if runes[from-1] == ' ' {
+			words++
+		}
+	}
+	for words := 0; to < len(runes) && words < snippetTokens/2; to++ {
+		if runes[to] == ' ' {
+			words++
+		}
+	}
+
+	snippet := strings.TrimSpace(string(runes[from:start]) + snippetOpen + string(runes[start:end]) + snippetClose + string(runes[end:to]))
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+var diacritics = strings.NewReplacer("ă", "a", "â", "a", "î", "i", "ș", "s", "ş", "s", "ț", "t", "ţ", "t")
+
+// foldText lowercases text and drops the Romanian diacritics.
+func foldText(text string) string {
+	return diacritics.Replace(strings.ToLower(text))
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package problems
+
+import (
+	"database/sql"
+	"errors"
+	"kncli/internal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestFoldText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Arbore", "arbore"},
+		{"Înălțime și lățime", "inaltime si latime"},
+		{"cedilla ş ţ", "cedilla s t"},
+	}
+	for _, tt := range tests {
+		got := foldText(tt.text)
+		if got != tt.want {
+			t.Errorf("foldText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+		// plainSnippet relies on positions matching between text and folded.
+		if len([]rune(got)) != len([]rune(tt.text)) {
+			t.Errorf("foldText(%q) changed the number of runes", tt.text)
+		}
+	}
+}
+
+func TestPlainWords(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{"arbore intervale", []string{"arbore", "intervale"}, false},
+		{`"Arbore de" intervale*`, []string{"arbore", "de", "intervale"}, false},
+		{"sum AND ONI", []string{"sum", "oni"}, false},
+		{"sum and oni", []string{"sum", "and", "oni"}, false}, // operators are upper case
+		{"sum NOT lazy", nil, true},
+		{"sum OR oni", nil, true},
+		{"NEAR(sum oni)", nil, true},
+		{`" * "`, nil, false},
+	}
+	for _, tt := range tests {
+		got, err := plainWords(tt.query)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("plainWords(%q) error = %v, want error %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !errors.Is(err, internal.ErrNoFTS5) {
+			t.Errorf("plainWords(%q) error = %v, want it to wrap ErrNoFTS5", tt.query, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("plainWords(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestPlainSnippet(t *testing.T) {
+	long := strings.Repeat("a ", 10) + "Țintă" + strings.Repeat(" b", 10)
+
+	tests := []struct {
+		text string
+		word string
+		want string
+	}{
+		{"Suma a două numere", "doua", "Suma a «două» numere"},
+		{long, "tinta", "…a a a a a «Țintă» b b b b b…"},
+	}
+	for _, tt := range tests {
+		if got := plainSnippet(tt.text, foldText(tt.text), tt.word); got != tt.want {
+			t.Errorf("plainSnippet(%q, %q) = %q, want %q", tt.text, tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSearchPlain(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := internal.MigrateDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	encode := func(text string) string {
+		encoded, _ := internal.EncodeBase64Text(text)
+		return encoded
+	}
+	_, err = db.Exec(`INSERT INTO problems (id, name, credits, languages) VALUES (1, 'sum', 'ONI', 'RO,EN'), (2, 'arbore', 'OJI', '');
+INSERT INTO problems (id, name, statement) VALUES (3, 'legacy', ?);
+INSERT INTO statements (problem_id, language, attachment, statement) VALUES (1, 'RO', 'statement-ro.md', ?), (1, 'EN', 'statement-en.md', ?);`,
+		encode("arbore arbore"), encode("Calculați suma"), encode("Compute the sum of the sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		id       int
+		language string
+	}
+	tests := []struct {
+		query    string
+		language string
+		want     []result
+	}{
+		{"sum", "", []result{{1, "EN"}, {1, "RO"}}}, // EN has more occurrences
+		{"sum", "RO", []result{{1, "RO"}}},
+		{"calculati oni", "", []result{{1, "RO"}}},
+		{"arbore", "", []result{{3, ""}, {2, ""}}},
+		{"arbore", "EN", nil},
+		{"missing", "", nil},
+	}
+	for _, tt := range tests {
+		matches, err := searchPlain(db, tt.query, tt.language)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []result
+		for _, match := range matches {
+			got = append(got, result{match.Id, match.Language})
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("searchPlain(%q, %q) = %v, want %v", tt.query, tt.language, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package profile
+
+import (
+	"fmt"
+	"kncli/internal"
+
+	"github.com/spf13/cobra"
+)
+
+var ProfileCmd = &cobra.Command{
+	Use:   "profile [command] ...",
+	Short: "Manage account profiles",
+}
+
+var listProfilesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles. The active one is marked with *.",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		listProfiles()
+	},
+}
+
+var useProfileCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Switch to a profile, creating it if needed.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		useProfile(args[0])
+	},
+}
+
+var removeProfileCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Remove a profile with its token and settings.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		removeProfile(args[0])
+	},
+}
+
+func init() {
+	ProfileCmd.AddCommand(listProfilesCmd)
+	ProfileCmd.AddCommand(useProfileCmd)
+	ProfileCmd.AddCommand(removeProfileCmd)
+}
+
+func listProfiles() {
+	names, err := internal.Profiles()
+	if err != nil {
+		internal.LogError(err)
+	}
+
+	for _, name := range names {
+		marker := " "
+		if name == internal.Profile() {
+			marker = "*"
+		}
+		status := "signed out"
+		if internal.HasToken(name) {
+			status = "signed in"
+		}
+		fmt.Printf("%s %s (%s)\n", marker, name, status)
+	}
+}
+
+func useProfile(name string) {
+	if err := internal.ValidateProfileName(name); err != nil {
+		internal.LogError(err)
+	}
+
+	created := !internal.ProfileExists(name)
+	if err := internal.CreateProfile(name); err != nil {
+		internal.LogError(fmt.Errorf("failed to create profile: %w", err))
+	}
+	if err := internal.SaveProfile(name); err != nil {
+		internal.LogError(fmt.Errorf("failed to switch profile: %w", err))
+	}
+
+	if created {
+		fmt.Printf("Created profile %q. Run 'signin' to add an account to it.\n", name)
+	}
+	fmt.Printf("Now using profile %q.\n", name)
+}
+
+func removeProfile(name string) {
+	if err := internal.RemoveProfile(name); err != nil {
+		internal.LogError(err)
+	}
+	fmt.Printf("Profile %q removed.\n", name)
+}
@@ -25,11 +25,21 @@ var codeBlocksProjectFile = false
 var cMakeProjectFile = false
 
 var InitProjectCmd = &cobra.Command{
-	Use:   "init [Problem ID] [Language]",
+	Use:   "init [Problem ID] [Language (optional, config key language)]",
 	Short: "Create a project (statement, assets and source file for your chosen language)",
-	Args:  cobra.ExactArgs(2),
+	Args:  cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
-		action := func() { initProject(args[0], args[1]) }
+		codeBlocksProjectFile = internal.BoolFlagSetting(cmd, "codeblocksproject", "", internal.CONFIG_PROJECT_CODEBLOCKS)
+		cMakeProjectFile = internal.BoolFlagSetting(cmd, "cmakeproject", "", internal.CONFIG_PROJECT_CMAKE)
+
+		var language string
+		if len(args) > 1 {
+			language = args[1]
+		} else {
+			language = submission.DefaultLanguage()
+		}
+
+		action := func() { initProject(args[0], language) }
 		if err := spinner.New().Title("Please wait...").Action(action).Run(); err != nil {
 			internal.LogError(err)
 			return
@@ -39,8 +49,8 @@ var InitProjectCmd = &cobra.Command{
 
 func init() {
 
-	InitProjectCmd.Flags().BoolVarP(&codeBlocksProjectFile, "codeblocksproject", "b", false, "Create a codeblocks project.")
-	InitProjectCmd.Flags().BoolVarP(&cMakeProjectFile, "cmakeproject", "c", false, "Create a CMake project.")
+	InitProjectCmd.Flags().BoolVarP(&codeBlocksProjectFile, "codeblocksproject", "b", false, "Create a codeblocks project (config key project_codeblocks).")
+	InitProjectCmd.Flags().BoolVarP(&cMakeProjectFile, "cmakeproject", "c", false, "Create a CMake project (config key project_cmake).")
 }
 
 func extractFunctionDeclarations(HeaderFileContent string) []string {
@@ -180,9 +190,14 @@ func AuxiliaryModifications(problemID, ProgrammingLanguage, CurrentWorkingDir, N
 		return
 	}
 
-	ProblemStatement, err := problem.PrintStatement(problemID, "RO", 2)
+	language, fallback := problem.StatementLanguage(""), "EN"
+	if language == "EN" {
+		fallback = "RO"
+	}
+
+	ProblemStatement, err := problem.PrintStatement(problemID, language, 2)
 	if err != nil && err.Error() == internal.NOLANG {
-		ProblemStatement, err = problem.PrintStatement(problemID, "EN", 2)
+		ProblemStatement, err = problem.PrintStatement(problemID, fallback, 2)
 		if err != nil {
 			internal.LogError(fmt.Errorf("error fetching problem statement: %v", err))
 			return
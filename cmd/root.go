@@ -6,14 +6,21 @@
 package cmd
 
 import (
+	"context"
+	"kncli/api"
+	"kncli/cmd/config"
 	contest "kncli/cmd/contests"
 	db "kncli/cmd/database"
 	problem "kncli/cmd/problems"
+	"kncli/cmd/profile"
 	"kncli/cmd/project"
 	"kncli/cmd/submission"
 	"kncli/cmd/user"
 	"kncli/internal"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/spf13/cobra"
 )
@@ -26,22 +33,111 @@ var RootCmd = &cobra.Command{
 with the Kilonova competitive programming platform. It enables users to view statements, 
 search for problems, submit solutions, and retrieve submission results directly from 
 the terminal.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		internal.SetConfigFile(changedFlag(cmd, "config"))
+		if err := internal.SetProfile(changedFlag(cmd, "profile")); err != nil {
+			internal.LogError(err)
+		}
+
+		flags := internal.NetworkFlags{
+			APIURL:    changedFlag(cmd, "api-url"),
+			Retries:   changedFlag(cmd, "retries"),
+			RateLimit: changedFlag(cmd, "rate-limit"),
+			NoCache:   changedFlag(cmd, "no-cache"),
+			Refresh:   changedFlag(cmd, "refresh"),
+			Trace:     traceLevel(cmd),
+
+			Timeout:        changedFlag(cmd, "timeout"),
+			ConnectTimeout: changedFlag(cmd, "connect-timeout"),
+			Proxy:          changedFlag(cmd, "proxy"),
+			CAFile:         changedFlag(cmd, "ca-file"),
+		}
+		if err := internal.ConfigureClient(flags); err != nil {
+			internal.LogError(err)
+		}
+		if err := internal.SetOutputFormat(changedFlag(cmd, "output")); err != nil {
+			internal.LogError(err)
+		}
+		if err := internal.SetNoTUI(changedFlag(cmd, "no-tui")); err != nil {
+			internal.LogError(err)
+		}
+		if err := internal.SetOutputTemplate(changedFlag(cmd, "format"), changedFlag(cmd, "template")); err != nil {
+			internal.LogError(err)
+		}
+		internal.UnlockToken()
+	},
+}
+
+// changedFlag returns the value of a flag, or "" when it was not given on the command line.
+func changedFlag(cmd *cobra.Command, name string) string {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil || !flag.Changed {
+		return ""
+	}
+	return flag.Value.String()
+}
+
+// traceLevel combines --trace and -v/-vv into a trace level, "" when neither was given.
+func traceLevel(cmd *cobra.Command) string {
+	if level := changedFlag(cmd, "verbose"); level != "" {
+		return level
+	}
+	if changedFlag(cmd, "trace") == "true" {
+		return strconv.Itoa(api.TraceRequests)
+	}
+	return ""
 }
 
 func Execute() {
-	err := RootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Commands clean up once ctx is cancelled; a second signal kills the process.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	internal.SetContext(ctx)
+	err := RootCmd.ExecuteContext(ctx)
 	if err != nil {
 		RootCmd.Println(err)
 		os.Exit(1)
 	}
+	if ctx.Err() != nil {
+		os.Exit(internal.EXIT_INTERRUPTED)
+	}
 }
 
 func init() {
+	RootCmd.PersistentFlags().String("config", "", "Config file to use instead of $XDG_CONFIG_HOME/kncli/config.yaml (env KNCLI_CONFIG).")
+	RootCmd.PersistentFlags().String("profile", "", "Account profile to use (env KNCLI_PROFILE, default set by 'profile use').")
+	RootCmd.PersistentFlags().StringP("output", "O", internal.OUTPUT_TABLE, "Output of listing commands: table, json, csv, tsv or yaml (env KNCLI_OUTPUT, config key output).")
+	RootCmd.PersistentFlags().String("format", "", "Go template applied to each result, e.g. '{{.Id}} {{.Score}}'.")
+	RootCmd.PersistentFlags().String("template", "", "Template file for --format, or the name of one in the templates folder of the config dir.")
+	RootCmd.PersistentFlags().Bool("no-tui", false, "Print tables and statements instead of opening a viewer; implied when stdout isn't a terminal (env KNCLI_NO_TUI, config key no_tui).")
+	RootCmd.PersistentFlags().String("api-url", "", "Base URL of the Kilonova instance (env KNCLI_API_URL, config key api_url).")
+	RootCmd.PersistentFlags().Int("retries", api.DefaultRetryPolicy.MaxRetries, "Retries for failed GET requests (env KNCLI_RETRIES, config key retries).")
+	RootCmd.PersistentFlags().Float64("rate-limit", internal.DEFAULT_RATE_LIMIT, "Maximum requests per second, 0 to disable (env KNCLI_RATE_LIMIT, config key rate_limit).")
+	RootCmd.PersistentFlags().Bool("no-cache", false, "Don't read or store cached API responses (env KNCLI_NO_CACHE, config key no_cache).")
+	RootCmd.PersistentFlags().Bool("refresh", false, "Ignore cached API responses and fetch them again.")
+	RootCmd.PersistentFlags().Bool("trace", false, "Print every HTTP request to stderr, same as -v (env KNCLI_TRACE=1|2).")
+	RootCmd.PersistentFlags().CountP("verbose", "v", "Trace HTTP requests to stderr; -vv also prints headers and bodies.")
+	RootCmd.PersistentFlags().Duration("timeout", api.DefaultHTTPOptions.Timeout, "Maximum duration of a request, 0 for none (env KNCLI_TIMEOUT, config key timeout).")
+	RootCmd.PersistentFlags().Duration("connect-timeout", api.DefaultHTTPOptions.ConnectTimeout, "Maximum time to connect to the server (env KNCLI_CONNECT_TIMEOUT, config key connect_timeout).")
+	RootCmd.PersistentFlags().String("proxy", "", "Proxy URL, overrides HTTPS_PROXY (config key proxy).")
+	RootCmd.PersistentFlags().String("ca-file", "", "PEM bundle of extra trusted root certificates (env KNCLI_CA_FILE, config key ca_file).")
+
+	RootCmd.AddCommand(config.ConfigCmd)
+
 	RootCmd.AddCommand(contest.ContestCmd)
 
 	RootCmd.AddCommand(problem.GetAssetsCmd)
 	RootCmd.AddCommand(problem.SearchCmd)
 	RootCmd.AddCommand(problem.PrintStatementCmd)
+	RootCmd.AddCommand(problem.PrintProblemInfoCmd)
+
+	RootCmd.AddCommand(profile.ProfileCmd)
 
 	RootCmd.AddCommand(project.InitProjectCmd)
 	RootCmd.AddCommand(project.GetRandPbCmd)
@@ -50,6 +146,7 @@ func init() {
 	RootCmd.AddCommand(submission.UploadCodeCmd)
 	RootCmd.AddCommand(submission.SubmissionCmd)
 
+	RootCmd.AddCommand(user.AuthCmd)
 	RootCmd.AddCommand(user.SettingsCmd)
 	RootCmd.AddCommand(user.SigninCmd)
 	RootCmd.AddCommand(user.LogoutCmd)
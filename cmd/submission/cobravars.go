@@ -14,13 +14,16 @@ import (
 )
 
 var UploadCodeCmd = &cobra.Command{
-	Use:   "submit [ID] [LANGUAGE] [FILENAME] [Contest ID (optional)]",
+	Use:   "submit [ID] [LANGUAGE (optional, config key language)] [FILENAME] [Contest ID (optional)]",
 	Short: "Submit solution to problem. (online)",
-	Args:  cobra.MinimumNArgs(3),
+	Args:  cobra.RangeArgs(2, 4),
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 3 {
+		switch len(args) {
+		case 2:
+			uploadCode(args[0], DefaultLanguage(), args[1], "NO")
+		case 3:
 			uploadCode(args[0], args[1], args[2], "NO")
-		} else {
+		default:
 			uploadCode(args[0], args[1], args[2], args[3])
 		}
 	},
@@ -43,24 +46,66 @@ var SubmissionCmd = &cobra.Command{
 var PrintSubmissionsCmd = &cobra.Command{
 	Use:   "list [Problem ID or all (all problems)] [User ID, me (personal submissions), all (all users)] [1st page] [last page]",
 	Short: "View sent submissions to a problem. (online)",
-	Args:  cobra.ExactArgs(4),
+	Long: `View sent submissions to a problem. (online)
+Omitted arguments default to all problems, the user set with the config key submissions_user
+(all users otherwise), the first page and as many pages as the config key submissions_pages (1 otherwise).
+In a terminal, the submissions open in a browser that loads more pages while scrolling, starting at
+the first page; Enter shows the details and tests of a submission.`,
+	Args: cobra.RangeArgs(0, 4),
 	Run: func(cmd *cobra.Command, args []string) {
-		FirstPage, err := strconv.Atoi(args[2])
-		if err != nil {
-			utility.LogError(fmt.Errorf("invalid first page number: %v", err))
-			return
+		ProblemID := "all"
+		if len(args) > 0 {
+			ProblemID = args[0]
 		}
 
-		LastPage, err := strconv.Atoi(args[3])
-		if err != nil {
-			utility.LogError(fmt.Errorf("invalid last page number: %v", err))
-			return
+		UserID := utility.DEFAULT_SUBMISSIONS_USER
+		if len(args) > 1 {
+			UserID = args[1]
+		} else if value, ok := utility.ConfigValue(utility.CONFIG_SUBMISSIONS_USER); ok {
+			UserID = value
+		}
+
+		FirstPage := 1
+		if len(args) > 2 {
+			page, err := strconv.Atoi(args[2])
+			if err != nil {
+				utility.LogError(fmt.Errorf("invalid first page number: %v", err))
+				return
+			}
+			FirstPage = page
+		}
+
+		LastPage := FirstPage + submissionPages() - 1
+		if len(args) > 3 {
+			page, err := strconv.Atoi(args[3])
+			if err != nil {
+				utility.LogError(fmt.Errorf("invalid last page number: %v", err))
+				return
+			}
+			LastPage = page
 		}
 
-		printSubmissions(args[0], args[1], FirstPage, LastPage)
+		if utility.UseTUI() && !utility.StructuredOutput() {
+			browseSubmissions(ProblemID, UserID, FirstPage)
+			return
+		}
+		printSubmissions(ProblemID, UserID, FirstPage, LastPage)
 	},
 }
 
+// submissionPages returns how many pages 'submission list' shows when the last page is omitted.
+func submissionPages() int {
+	value, ok := utility.ConfigValue(utility.CONFIG_SUBMISSIONS_PAGES)
+	if !ok {
+		return utility.DEFAULT_SUBMISSIONS_PAGES
+	}
+	pages, err := strconv.Atoi(value)
+	if err != nil || pages <= 0 {
+		utility.LogError(fmt.Errorf("invalid %s %q: must be a positive number", utility.CONFIG_SUBMISSIONS_PAGES, value))
+	}
+	return pages
+}
+
 var PrintSubmissionInfoCmd = &cobra.Command{
 	Use:   "info [Submission ID]",
 	Short: "View a detailed description of a sent submission. (online)",
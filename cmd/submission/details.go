@@ -6,14 +6,13 @@
 package submission
 
 import (
-	"bytes"
 	"embed"
 	b64 "encoding/base64"
-	"encoding/json"
 	"fmt"
+	"io"
+	"kncli/api"
 	problem "kncli/cmd/problems"
 	"kncli/internal"
-	u "net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -36,7 +35,7 @@ func downloadSource(submissionId, code string) {
 	}
 
 	downFile := filepath.Join(homedir, "source_"+submissionId+".txt")
-	if err := os.WriteFile(downFile, []byte(code), 0644); err != nil {
+	if err := internal.WriteFileAtomic(downFile, []byte(code), 0644); err != nil {
 		internal.LogError(fmt.Errorf("failed to write source code to file %q: %w", downFile, err))
 		return
 	}
@@ -45,68 +44,89 @@ func downloadSource(submissionId, code string) {
 }
 
 func printDetailsSubmission(submissionId string) {
-	var details SubmissionDetails
-
-	url := fmt.Sprintf(internal.URL_LATEST_SUBMISSION, submissionId)
-
-	formData := u.Values{
-		"id": {submissionId},
-	}
-
-	ResponseBody, err := internal.MakeGetRequest(url, bytes.NewBufferString(formData.Encode()), internal.RequestNone)
+	details, err := internal.Client().GetSubmission(internal.Context(), submissionId)
 	if err != nil {
 		internal.LogError(fmt.Errorf("error fetching submission details: %w", err))
 		return
 	}
 
-	if err := json.Unmarshal(ResponseBody, &details); err != nil {
-		internal.LogError(fmt.Errorf("error unmarshalling response: %w", err))
+	code, err := b64.StdEncoding.DecodeString(details.Code)
+	if err != nil {
+		internal.LogError(fmt.Errorf("error decoding source code: %w", err))
 		return
 	}
 
-	if details.Status != internal.SUCCESS {
-		internal.LogError(fmt.Errorf("submission fetch failed with status: %v", details.Status))
-		return
+	if !internal.PrintOutput(*details) {
+		formattedTime, err := internal.ParseTime(details.CreatedAt)
+		if err != nil {
+			internal.LogError(err)
+			return
+		}
+
+		ProblemID := strconv.Itoa(details.ProblemID)
+		fmt.Println(problem.GetProblemInfoText(ProblemID))
+
+		if err := printTemplateSubmission(os.Stdout, *details, formattedTime, code); err != nil {
+			internal.LogError(err)
+			return
+		}
+	}
+
+	if shouldDownload {
+		action := func() { downloadSource(submissionId, string(code)) }
+		if err := spinner.New().Title("Downloading...").Action(action).Run(); err != nil {
+			internal.LogError(fmt.Errorf("error during downloading source code for submission #%s: %w", submissionId, err))
+			return
+		}
 	}
+}
 
-	formattedTime, err := internal.ParseTime(details.Data.CreatedAt)
+// submissionDetailsText returns the details of a submission followed by its subtests.
+func submissionDetailsText(submissionId string) (string, error) {
+	details, err := internal.Client().GetSubmission(internal.Context(), submissionId)
 	if err != nil {
-		internal.LogError(err)
-		return
+		return "", fmt.Errorf("error fetching submission details: %w", err)
 	}
 
-	ProblemID := strconv.Itoa(details.Data.ProblemID)
-	fmt.Println(problem.GetProblemInfoText(ProblemID))
+	formattedTime, err := internal.ParseTime(details.CreatedAt)
+	if err != nil {
+		return "", err
+	}
 
-	code, err := b64.StdEncoding.DecodeString(details.Data.Code)
+	code, err := b64.StdEncoding.DecodeString(details.Code)
 	if err != nil {
-		internal.LogError(fmt.Errorf("error decoding source code: %w", err))
-		return
+		return "", fmt.Errorf("error decoding source code: %w", err)
 	}
 
-	printTemplateSubmission(details, formattedTime, code)
+	var text strings.Builder
+	fmt.Fprintf(&text, "Problem ID: #%d\n", details.ProblemID)
+	if err := printTemplateSubmission(&text, *details, formattedTime, code); err != nil {
+		return "", err
+	}
 
-	if shouldDownload {
-		action := func() { downloadSource(submissionId, string(code)) }
-		if err := spinner.New().Title("Downloading...").Action(action).Run(); err != nil {
-			internal.LogError(fmt.Errorf("error during downloading source code for submission #%s: %w", submissionId, err))
-			return
+	if len(details.Subtests) > 0 {
+		text.WriteString("\nTests:\n")
+		Columns, Rows := subtestTable(*details)
+		if err := internal.PrintTable(&text, Columns, Rows); err != nil {
+			return "", err
 		}
 	}
+
+	return text.String(), nil
 }
 
-func printTemplateSubmission(details SubmissionDetails, formattedTime string, code []byte) {
+func printTemplateSubmission(w io.Writer, details api.Submission, formattedTime string, code []byte) error {
 	submissionData := SubmissionDetailsTemplate{
-		ID:             details.Data.Id,
+		ID:             details.Id,
 		CreatedAt:      formattedTime,
-		Language:       details.Data.Language,
-		Score:          details.Data.Score,
-		MaxMemory:      details.Data.MaxMemory,
-		MaxTime:        details.Data.MaxTime,
-		CompileError:   details.Data.CompileError,
-		CompileMessage: details.Data.CompileMessage,
-		ContestID:      details.Data.ContestID,
-		Code:           formatCodeOutput(string(code), details.Data.Language),
+		Language:       details.Language,
+		Score:          details.Score,
+		MaxMemory:      details.MaxMemory,
+		MaxTime:        details.MaxTime,
+		CompileError:   details.CompileError,
+		CompileMessage: details.CompileMessage,
+		ContestID:      details.ContestID,
+		Code:           formatCodeOutput(string(code), details.Language),
 	}
 
 	if submissionData.Code == internal.ERROR {
@@ -115,14 +135,13 @@ func printTemplateSubmission(details SubmissionDetails, formattedTime string, co
 
 	tmpl, err := template.New("submissionDetails").Parse(internal.SubmissionTemplate)
 	if err != nil {
-		internal.LogError(fmt.Errorf("failed to parse template: %w", err))
-		return
+		return fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	if err := tmpl.Execute(os.Stdout, submissionData); err != nil {
-		internal.LogError(fmt.Errorf("failed to execute template: %w", err))
-		return
+	if err := tmpl.Execute(w, submissionData); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
 	}
+	return nil
 }
 
 func formatCodeOutput(code string, lang string) string {
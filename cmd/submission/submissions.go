@@ -6,11 +6,12 @@
 package submission
 
 import (
-	"encoding/json"
 	"fmt"
+	"kncli/api"
 	"kncli/internal"
 
 	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 var shouldDownload = false
@@ -24,19 +25,6 @@ func init() {
 
 // print submissions
 
-func getSubmissionURL(UserID, ProblemID string, OffSet int) string {
-	switch {
-	case UserID == "all" && ProblemID == "all":
-		return fmt.Sprintf(internal.URL_SUBMISSION_LIST_NO_FILTER, OffSet)
-	case UserID == "all":
-		return fmt.Sprintf(internal.URL_SUBMISSION_LIST_NO_USER, OffSet, ProblemID)
-	case ProblemID == "all":
-		return fmt.Sprintf(internal.URL_SUBMISSION_LIST_NO_PROBLEM, OffSet, UserID)
-	default:
-		return fmt.Sprintf(internal.URL_SUBMISSION_LIST, OffSet, ProblemID, UserID)
-	}
-}
-
 func printSubmissions(ProblemID, UserID string, FirstPage, LastPage int) {
 	if UserID == "me" {
 		UserID = internal.GetUserID()
@@ -52,53 +40,38 @@ func printSubmissions(ProblemID, UserID string, FirstPage, LastPage int) {
 		return
 	}
 
-	var DataSubmissions SubmissionList
 	var Rows []table.Row
+	var Submissions []api.Submission
 	var count = -1
-	startOffset := (FirstPage - 1) * 50
-	endOffset := max((LastPage-1)*50, 50)
-
-	for OffSet := max(startOffset, 0); (OffSet < count || count < 0) && OffSet < endOffset; OffSet += 50 {
-		url := getSubmissionURL(UserID, ProblemID, OffSet)
 
-		ResponseBody, err := internal.MakeGetRequest(url, nil, internal.RequestFormAuth)
-		if err != nil {
-			internal.LogError(err)
-			continue
+	for _, OffSet := range pageOffsets(FirstPage, LastPage) {
+		if count >= 0 && OffSet >= count {
+			break
 		}
+		filter := api.SubmissionFilter{ProblemID: ProblemID, UserID: UserID, Offset: OffSet}
 
-		if err := json.Unmarshal(ResponseBody, &DataSubmissions); err != nil {
+		DataSubmissions, err := internal.Client().ListSubmissions(internal.Context(), filter)
+		if err != nil {
 			internal.LogError(err)
 			continue
 		}
 
-		count = DataSubmissions.Data.Count
+		count = DataSubmissions.Count
+		Submissions = append(Submissions, DataSubmissions.Submissions...)
 
-		for _, problem := range DataSubmissions.Data.Submissions {
-			formattedTime, err := internal.ParseTime(problem.CreatedAt)
+		for _, problem := range DataSubmissions.Submissions {
+			Row, err := submissionRow(problem)
 			if err != nil {
 				internal.LogError(err)
 				continue
 			}
 
-			Rows = append(Rows, table.Row{
-				fmt.Sprintf("%d", problem.ProblemID),
-				fmt.Sprintf("%d", problem.UserID),
-				fmt.Sprintf("%d", problem.Id),
-				formattedTime,
-				problem.Language,
-				fmt.Sprintf("%.0f", problem.Score),
-			})
+			Rows = append(Rows, Row)
 		}
 	}
 
-	Columns := []table.Column{
-		{Title: "Pb ID", Width: 5},
-		{Title: "User ID", Width: 7},
-		{Title: "Submission ID", Width: 12},
-		{Title: "Time", Width: 25},
-		{Title: "Language", Width: 10},
-		{Title: "Score", Width: 10},
+	if internal.PrintOutput(Submissions) {
+		return
 	}
 
 	if len(Rows) == 0 {
@@ -106,20 +79,87 @@ func printSubmissions(ProblemID, UserID string, FirstPage, LastPage int) {
 		return
 	}
 
-	internal.RenderTable(Columns, Rows, 1)
+	internal.RenderTable(submissionColumns, Rows, nil)
 }
 
-func CheckLanguages(ProblemID string, useCase int) []string {
-	url := fmt.Sprintf(internal.URL_LANGS_PB, ProblemID)
-	ResponseBody, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
+var submissionColumns = []table.Column{
+	{Title: "Pb ID", Width: 5},
+	{Title: "User ID", Width: 7},
+	{Title: "Submission ID", Width: 12},
+	{Title: "Time", Width: 25},
+	{Title: "Language", Width: 10},
+	{Title: "Score", Width: 10},
+}
+
+// pageOffsets returns the offset of every page of 50 submissions from
+// FirstPage to LastPage, both included.
+func pageOffsets(FirstPage, LastPage int) []int {
+	var offsets []int
+	for page := FirstPage; page <= LastPage; page++ {
+		offsets = append(offsets, (page-1)*50)
+	}
+	return offsets
+}
+
+func submissionRow(problem api.Submission) (table.Row, error) {
+	formattedTime, err := internal.ParseTime(problem.CreatedAt)
 	if err != nil {
-		internal.LogError(fmt.Errorf("failed to make request for problem ID %s: %w", ProblemID, err))
-		return nil
+		return nil, err
+	}
+
+	return table.Row{
+		fmt.Sprintf("%d", problem.ProblemID),
+		fmt.Sprintf("%d", problem.UserID),
+		fmt.Sprintf("%d", problem.Id),
+		formattedTime,
+		problem.Language,
+		fmt.Sprintf("%.0f", problem.Score),
+	}, nil
+}
+
+// browseSubmissions opens the submissions in a table that loads the next page
+// when scrolling past the last row, starting at FirstPage. Enter shows the
+// details and subtests of a submission.
+func browseSubmissions(ProblemID, UserID string, FirstPage int) {
+	if UserID == "me" {
+		UserID = internal.GetUserID()
+	}
+
+	if FirstPage <= 0 {
+		internal.LogError(fmt.Errorf("invalid pages: FirstPage must be a positive integer"))
+		return
+	}
+
+	load := func(offset int) ([]table.Row, int, error) {
+		filter := api.SubmissionFilter{ProblemID: ProblemID, UserID: UserID, Offset: offset}
+
+		DataSubmissions, err := internal.Client().ListSubmissions(internal.Context(), filter)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var Rows []table.Row
+		for _, problem := range DataSubmissions.Submissions {
+			Row, err := submissionRow(problem)
+			if err != nil {
+				return nil, 0, err
+			}
+			Rows = append(Rows, Row)
+		}
+		return Rows, DataSubmissions.Count, nil
 	}
 
-	var langs Languages
-	if err := json.Unmarshal(ResponseBody, &langs); err != nil {
-		internal.LogError(fmt.Errorf("error unmarshalling languages response: %w", err))
+	open := internal.OpenText(func(row table.Row) (string, error) {
+		return submissionDetailsText(row[2])
+	})
+
+	internal.RunScreen(internal.NewPagedTable(submissionColumns, (FirstPage-1)*50, load, open), tea.WithAltScreen())
+}
+
+func CheckLanguages(ProblemID string, useCase int) []string {
+	langs, err := internal.Client().ProblemLanguages(internal.Context(), ProblemID)
+	if err != nil {
+		internal.LogError(fmt.Errorf("failed to fetch languages for problem ID %s: %w", ProblemID, err))
 		return nil
 	}
 
@@ -132,15 +172,15 @@ func CheckLanguages(ProblemID string, useCase int) []string {
 	}
 }
 
-func printLanguages(langs Languages) {
-	for i, lang := range langs.Data {
+func printLanguages(langs []api.Language) {
+	for i, lang := range langs {
 		fmt.Printf("%d: %s\n", i+1, lang.Name)
 	}
 }
 
-func extractLanguageNames(langs Languages) []string {
+func extractLanguageNames(langs []api.Language) []string {
 	var listLangs []string
-	for _, lang := range langs.Data {
+	for _, lang := range langs {
 		listLangs = append(listLangs, lang.Name)
 	}
 	return listLangs
@@ -0,0 +1,30 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package submission
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPageOffsets(t *testing.T) {
+	tests := []struct {
+		first, last int
+		want        []int
+	}{
+		{1, 1, []int{0}},
+		{1, 2, []int{0, 50}},
+		{2, 2, []int{50}},
+		{1, 3, []int{0, 50, 100}},
+		{3, 3, []int{100}},
+		{3, 5, []int{100, 150, 200}},
+	}
+	for _, tt := range tests {
+		if got := pageOffsets(tt.first, tt.last); !slices.Equal(got, tt.want) {
+			t.Errorf("pageOffsets(%d, %d) = %v, want %v", tt.first, tt.last, got, tt.want)
+		}
+	}
+}
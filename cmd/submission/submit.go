@@ -6,139 +6,105 @@
 package submission
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
+	"kncli/api"
 	"kncli/internal"
-	"mime/multipart"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/charmbracelet/huh/spinner"
 )
 
+// DefaultLanguage returns the language set with KNCLI_LANGUAGE or in the
+// config of the active profile.
+func DefaultLanguage() string {
+	language, ok := internal.Setting("", internal.ENV_LANGUAGE, internal.CONFIG_LANGUAGE)
+	if !ok {
+		internal.LogError(fmt.Errorf("no language given and no default set (env %s, config key %s)", internal.ENV_LANGUAGE, internal.CONFIG_LANGUAGE))
+	}
+	return language
+}
+
 func uploadCode(id, language, file, contestID string) {
-	codeFile, err := os.Open(file)
+	code, err := os.ReadFile(file)
 	if err != nil {
 		internal.LogError(fmt.Errorf("failed to open code file %s: %w", file, err))
 		return
 	}
-	defer codeFile.Close()
 
-	var requestBody bytes.Buffer
-	writer := multipart.NewWriter(&requestBody)
-
-	if err := writeFormFields(writer, id, language); err != nil {
-		internal.LogError(err)
-		return
+	submit := api.SubmitRequest{
+		ProblemID: id,
+		Language:  language,
+		Filename:  file,
+		Code:      code,
 	}
-
-	if err := writeCodeFile(writer, file, codeFile); err != nil {
-		internal.LogError(err)
-		return
-	}
-
 	if contestID != "NO" {
-		if err := writeFormContest(writer, contestID); err != nil {
-			internal.LogError(err)
-			return
-		}
-	}
-
-	contentType := writer.FormDataContentType()
-
-	if err := writer.Close(); err != nil {
-		internal.LogError(fmt.Errorf("failed to close writer: %w", err))
-		return
+		submit.ContestID = contestID
 	}
 
-	ResponseBody, err := internal.MakePostRequest(internal.URL_SUBMIT, &requestBody, internal.RequestMultipartForm, contentType)
+	submissionID, err := internal.Client().Submit(internal.Context(), submit)
 	if err != nil {
-		internal.LogError(fmt.Errorf("error submitting code: %w", err))
-		return
-	}
-
-	var data Submit
-	if err := json.Unmarshal(ResponseBody, &data); err != nil {
-		handleSubmissionError(ResponseBody)
+		var (
+			unauthorized *api.UnauthorizedError
+			invalid      *api.ValidationError
+			limited      *api.RateLimitedError
+		)
+		switch {
+		case errors.Is(err, api.ErrNotAuthenticated), errors.As(err, &unauthorized):
+			internal.LogError(fmt.Errorf("you must sign in before submitting code"))
+		case errors.As(err, &invalid):
+			internal.LogError(fmt.Errorf("submission rejected: %s", invalid.Message))
+		case errors.As(err, &limited) && limited.RetryAfter > 0:
+			internal.LogError(fmt.Errorf("too many submissions, try again in %s", limited.RetryAfter.Round(time.Second)))
+		case errors.As(err, &limited):
+			internal.LogError(fmt.Errorf("too many submissions, try again later"))
+		default:
+			internal.LogError(fmt.Errorf("error submitting code: %w", err))
+		}
 		return
 	}
 
-	fmt.Printf("Submission sent: %s\nSubmission ID: %d\n", data.Status, data.Data)
-	checkSubmissionStatus(data.Data)
-}
-
-func writeFormFields(writer *multipart.Writer, id, language string) error {
-	if err := writer.WriteField("problem_id", id); err != nil {
-		return fmt.Errorf("failed to write problem_id field: %w", err)
-	}
-	if err := writer.WriteField("language", language); err != nil {
-		return fmt.Errorf("failed to write language field: %w", err)
-	}
-	return nil
-}
-
-func writeFormContest(writer *multipart.Writer, id string) error {
-	if err := writer.WriteField("contestID", id); err != nil {
-		return fmt.Errorf("failed to write contestID field: %w", err)
-	}
-	return nil
+	fmt.Printf("Submission sent: %s\nSubmission ID: %d\n", internal.SUCCESS, submissionID)
+	checkSubmissionStatus(submissionID)
 }
 
-func writeCodeFile(writer *multipart.Writer, file string, codeFile *os.File) error {
-	fileWriter, err := writer.CreateFormFile("code", file)
-	if err != nil {
-		return fmt.Errorf("failed to create form file for code: %w", err)
-	}
-	if _, err := io.Copy(fileWriter, codeFile); err != nil {
-		return fmt.Errorf("failed to copy code file content: %w", err)
-	}
-	return nil
-}
-
-func handleSubmissionError(ResponseBody []byte) {
-	var dataerr SubmitError
-	if err := json.Unmarshal(ResponseBody, &dataerr); err != nil {
-		internal.LogError(fmt.Errorf("failed to parse error response: %w", err))
-		return
-	}
-	internal.LogError(fmt.Errorf("status: %s\nmessage: %s", dataerr.Status, dataerr.Data))
-}
+const submissionPollInterval = 500 * time.Millisecond
 
 func checkSubmissionStatus(submissionID int) {
-	url := fmt.Sprintf(internal.URL_LATEST_SUBMISSION, strconv.Itoa(submissionID))
-
-	action := func() {
-		var dataLatestSubmit LatestSubmission
+	// The action runs while the spinner owns the terminal, so it only returns
+	// the result, which is printed, or the error logged, once the spinner stops.
+	var dataLatestSubmit *api.Submission
+	action := func(context.Context) error {
 		for {
-			ResponseBody, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
+			var err error
+			dataLatestSubmit, err = internal.Client().GetSubmission(internal.Context(), strconv.Itoa(submissionID))
 			if err != nil {
-				internal.LogError(fmt.Errorf("failed to get submission status: %w", err))
-				return
+				return fmt.Errorf("failed to get submission status: %w", err)
 			}
 
-			if err := json.Unmarshal(ResponseBody, &dataLatestSubmit); err != nil {
-				internal.LogError(fmt.Errorf("failed to parse latest submission response: %w", err))
-				return
+			if dataLatestSubmit.Status == "finished" {
+				return nil
 			}
 
-			if dataLatestSubmit.Data.Status == "finished" {
-				break
+			select {
+			case <-internal.Context().Done():
+				return fmt.Errorf("stopped waiting for submission %d, it is still being evaluated", submissionID)
+			case <-time.After(submissionPollInterval):
 			}
-
-			fmt.Print(".")
-		}
-
-		if dataLatestSubmit.Data.CompileError {
-			fmt.Println("\nCompilation failed! Score: 0")
-		} else {
-			fmt.Printf("\nSuccess! Score: %d\n", dataLatestSubmit.Data.Score)
 		}
 	}
 
-	if err := spinner.New().Title("Please wait...").Action(action).Run(); err != nil {
-		internal.LogError(fmt.Errorf("spinner error: %w", err))
+	if err := spinner.New().Title("Please wait...").ActionWithErr(action).Run(); err != nil {
+		internal.LogError(err)
 		return
 	}
+
+	if dataLatestSubmit.CompileError {
+		fmt.Println("Compilation failed! Score: 0")
+	} else {
+		fmt.Printf("Success! Score: %.0f\n", dataLatestSubmit.Score)
+	}
 }
@@ -6,12 +6,12 @@
 package submission
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/charmbracelet/bubbles/table"
+	"kncli/api"
 	"kncli/internal"
 	"strconv"
 	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
 )
 
 func init() {
@@ -19,22 +19,26 @@ func init() {
 }
 
 func ShowTestsSubmission(ID string) {
-	url := fmt.Sprintf(internal.URL_LATEST_SUBMISSION, ID)
-	body, err := internal.MakeGetRequest(url, nil, internal.RequestNone)
+	data, err := internal.Client().GetSubmission(internal.Context(), ID)
 	if err != nil {
 		internal.LogError(err)
+		return
 	}
 
-	var data SubmissionDetails
-	if err = json.Unmarshal(body, &data); err != nil {
-		internal.LogError(err)
+	if internal.PrintOutput(data.Subtests) {
+		return
 	}
 
+	Columns, Rows := subtestTable(*data)
+	internal.RenderTable(Columns, Rows, nil)
+}
+
+func subtestTable(data api.Submission) ([]table.Column, []table.Row) {
 	var Rows []table.Row
 
-	for _, test := range data.Data.Subtests {
+	for _, test := range data.Subtests {
 		Rows = append(Rows, table.Row{
-			strconv.Itoa(data.Data.ProblemID),
+			strconv.Itoa(data.ProblemID),
 			strconv.Itoa(test.ID), strings.TrimPrefix(test.Verdict, "translate:"),
 			strconv.FormatFloat(test.Time, 'f', -1, 64),
 			strconv.Itoa(test.Memory), strconv.Itoa(test.Percentage),
@@ -53,5 +57,5 @@ func ShowTestsSubmission(ID string) {
 		{Title: "Obtained", Width: 8},
 	}
 
-	internal.RenderTable(Columns, Rows, 1)
+	return Columns, Rows
 }
@@ -6,34 +6,64 @@
 package user
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"kncli/cmd/database"
+	"kncli/api"
 	"kncli/internal"
-	u "net/url"
 	"os"
-	"path/filepath"
+	"time"
 
 	"github.com/charmbracelet/huh"
 )
 
 // login
-func loginForm() (string, string) {
-	var username, password string
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("Username:").
-				Value(&username),
-			huh.NewInput().
-				Title("Password:").
-				Value(&password).
-				EchoMode(huh.EchoModePassword),
-		),
-	)
-
-	if err := form.Run(); err != nil {
+
+var (
+	signinUsername      string
+	signinPasswordStdin bool
+	signinTokenStdin    bool
+)
+
+// loginCredentials collects the username and password from the flags,
+// KNCLI_USERNAME/KNCLI_PASSWORD and, on a terminal, a form for whatever is
+// still missing.
+func loginCredentials() (string, string) {
+	username := signinUsername
+	if username == "" {
+		username = os.Getenv(internal.ENV_USERNAME)
+	}
+
+	var password string
+	if signinPasswordStdin {
+		var err error
+		if password, err = internal.ReadStdinSecret(); err != nil {
+			internal.LogError(fmt.Errorf("failed to read password: %w", err))
+		}
+	} else {
+		password = os.Getenv(internal.ENV_PASSWORD)
+	}
+
+	if username != "" && password != "" {
+		return username, password
+	}
+	if !internal.IsTerminal(os.Stdin) {
+		internal.LogError(fmt.Errorf("stdin is not a terminal: use --username with --password-stdin, or set %s and %s", internal.ENV_USERNAME, internal.ENV_PASSWORD))
+	}
+
+	var fields []huh.Field
+	if username == "" {
+		fields = append(fields, huh.NewInput().
+			Title("Username:").
+			Value(&username))
+	}
+	if password == "" {
+		fields = append(fields, huh.NewInput().
+			Title("Password:").
+			Value(&password).
+			EchoMode(huh.EchoModePassword))
+	}
+
+	if err := huh.NewForm(huh.NewGroup(fields...)).Run(); err != nil {
 		internal.LogError(err)
 		return internal.ERROR, internal.ERROR
 	}
@@ -41,101 +71,124 @@ func loginForm() (string, string) {
 	return username, password
 }
 
-func createLoginToken(response internal.KilonovaResponse) {
-	tokenFile := filepath.Join(internal.GetConfigDir(), internal.TOKENFILENAME)
-
-	file, err := os.Create(tokenFile)
+// importToken stores a session token read from stdin after checking that the server accepts it.
+func importToken() {
+	token, err := internal.ReadStdinSecret()
 	if err != nil {
-		internal.LogError(fmt.Errorf("error creating file: %v", err))
-		return
+		internal.LogError(fmt.Errorf("failed to read token: %w", err))
 	}
-	defer file.Close()
 
-	encryptedToken, err := internal.Encrypt(response.Data)
+	client := *internal.Client()
+	client.Tokens = api.TokenFunc(func() (string, error) { return token, nil })
+	user, err := client.Self(internal.Context())
 	if err != nil {
-		internal.LogError(fmt.Errorf("error encrypting token: %v", err))
-		return
+		internal.LogError(fmt.Errorf("the token was rejected: %w", err))
 	}
 
-	if err := os.WriteFile(tokenFile, []byte(encryptedToken), 0644); err != nil {
-		internal.LogError(fmt.Errorf("error writing auth token to file: %v", err))
-		return
-	}
+	createLoginToken(token)
+	recordSessionExpiry()
+	fmt.Printf("Token imported, signed in as %s.\n", user.Name)
 }
 
-func login(username, password string) {
-	formData := u.Values{
-		"username": {username},
-		"password": {password},
+func createLoginToken(token string) {
+	if err := internal.WriteToken(token); err != nil {
+		internal.LogError(err)
 	}
+}
 
-	ResponseBody, err := internal.MakePostRequest(internal.URL_LOGIN, bytes.NewBufferString(formData.Encode()), internal.RequestFormGuest)
+func login(username, password string) {
+	token, err := internal.Client().Login(internal.Context(), username, password)
 	if err != nil {
 		internal.LogError(fmt.Errorf("login failed: %v", err))
 		return
 	}
 
-	if !bytes.Contains(ResponseBody, []byte(internal.SUCCESS)) {
-		internal.LogError(fmt.Errorf("login failed: invalid credentials"))
-		return
-	}
-
-	var response internal.KilonovaResponse
-	if err := json.Unmarshal(ResponseBody, &response); err != nil {
-		internal.LogError(fmt.Errorf("error parsing response: %v", err))
-		return
-	}
-
-	createLoginToken(response)
+	createLoginToken(token)
+	recordSessionExpiry()
 
 	fmt.Println("Login successful!")
+}
 
-	if !internal.DBExists() {
-		database.CreateDB()
+// recordSessionExpiry learns when a new session expires. Login doesn't return
+// it, but extending the fresh session does.
+func recordSessionExpiry() {
+	if _, err := internal.RefreshSessionExpiry(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: couldn't get the session expiry: %v\n", err)
 	}
 }
 
 // logout
 
 func removeTokenFile() {
-	tokenFile := filepath.Join(internal.GetConfigDir(), internal.TOKENFILENAME)
-	_ = os.Remove(tokenFile)
+	internal.RemoveToken()
 }
 
 func logout() {
-	JSONData := []byte(`{"key": "value"}`)
-	ResponseBody, err := internal.MakePostRequest(internal.URL_LOGOUT, bytes.NewBuffer(JSONData), internal.RequestFormAuth)
-	if err != nil {
-		internal.LogError(err)
+	// A token that can't be decrypted can't be logged out on the server
+	// either, but it can still be removed.
+	if _, err := internal.ReadToken(); err != nil {
+		fmt.Println("Removed the saved token, which couldn't be decrypted.")
+		removeTokenFile()
 		return
 	}
 
-	if bytes.Contains(ResponseBody, []byte(internal.SUCCESS)) {
-		fmt.Println("Logged out successfully!")
-		removeTokenFile()
+	if err := internal.Client().Logout(internal.Context()); err != nil {
+		internal.LogError(err)
+		return
 	}
+
+	fmt.Println("Logged out successfully!")
+	removeTokenFile()
 }
 
 func extendSession() {
-	ResponseBody, err := internal.MakePostRequest(internal.URL_EXTEND_SESSION, nil, internal.RequestFormAuth)
+	expiry, err := internal.RefreshSessionExpiry()
 	if err != nil {
 		internal.LogError(err)
 		return
 	}
 
-	var resp internal.KilonovaResponse
-	if err := json.Unmarshal(ResponseBody, &resp); err != nil {
-		internal.LogError(fmt.Errorf("error unmarshalling response: %s", err))
+	fmt.Println("Your session has been extended until ", expiry.Format("2006-01-02 15:04:05"))
+}
+
+// auth status
+
+func authStatus() {
+	fmt.Println("Profile:", internal.Profile())
+
+	if token, err := internal.ReadToken(); err != nil {
+		fmt.Println("Status:", err)
+		return
+	} else if token == "" {
+		fmt.Println("Status: signed out")
+		return
+	}
+
+	user, err := internal.Client().Self(internal.Context())
+	var unauthorized *api.UnauthorizedError
+	if errors.As(err, &unauthorized) {
+		fmt.Println("Status: session expired or revoked, sign in again")
 		return
 	}
+	if err != nil {
+		internal.LogError(fmt.Errorf("error fetching user details: %w", err))
+	}
+	fmt.Printf("Status: signed in as %s (ID %d)\n", user.Name, user.Id)
 
-	if resp.Status == internal.SUCCESS {
-		formattedTime, err := internal.ParseTime(resp.Data)
-		if err != nil {
-			internal.LogError(fmt.Errorf("error parsing time: %s", err))
-			return
+	if expiry, ok := internal.SessionExpiry(); ok {
+		remaining := "expired"
+		if time.Until(expiry) > 0 {
+			remaining = "in " + internal.FormatDuration(time.Until(expiry))
 		}
-		fmt.Println("Your session has been extended until ", formattedTime)
+		fmt.Printf("Expires: %s (%s)\n", expiry.Local().Format("2006-01-02 15:04"), remaining)
+	} else {
+		fmt.Println("Expires: unknown (run 'settings extendsession' to find out)")
+	}
+
+	autoExtend, window := internal.SessionAutoExtend()
+	if autoExtend {
+		fmt.Printf("Auto-extend: on, within %s of the expiry\n", internal.FormatDuration(window))
+	} else {
+		fmt.Println("Auto-extend: off")
 	}
-	fmt.Println(resp.Data)
 }
@@ -7,7 +7,9 @@ package user
 
 import (
 	"fmt"
+	"kncli/cmd/database"
 	utility "kncli/internal"
+	"os"
 
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/spf13/cobra"
@@ -19,19 +21,52 @@ var SettingsCmd = &cobra.Command{
 }
 
 var SigninCmd = &cobra.Command{
-	Use:   "signin [username] [password]",
+	Use:   "signin",
 	Short: "Sign in to your account. (online)",
-	Args:  cobra.ExactArgs(0),
+	Long: `Sign in to your account. Without flags a form asks for the username and password.
+In scripts, use --username with --password-stdin, set KNCLI_USERNAME and KNCLI_PASSWORD,
+or import an existing session token with --token-stdin.`,
+	Args: cobra.ExactArgs(0),
 	Run: func(cmd *cobra.Command, args []string) {
-		username, password := loginForm()
-		action := func() { login(username, password) }
-		if err := spinner.New().Title("Please wait...").Action(action).Run(); err != nil {
-			utility.LogError(err)
-			return
+		if signinTokenStdin && signinPasswordStdin {
+			utility.LogError(fmt.Errorf("--token-stdin and --password-stdin can't be used together"))
+		}
+
+		if signinTokenStdin {
+			importToken()
+		} else {
+			username, password := loginCredentials()
+			action := func() { login(username, password) }
+			if !utility.IsTerminal(os.Stdout) {
+				action()
+			} else if err := spinner.New().Title("Please wait...").Action(action).Run(); err != nil {
+				utility.LogError(err)
+				return
+			}
+		}
+
+		// Outside the spinner, which would draw over the progress bar. Scripts
+		// can create the database explicitly with 'database create'.
+		if !utility.DBExists() && utility.IsTerminal(os.Stdout) {
+			database.CreateDB(database.Jobs(nil))
 		}
 	},
 }
 
+var AuthCmd = &cobra.Command{
+	Use:   "auth [command] ...",
+	Short: "Inspect the current session",
+}
+
+var AuthStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the signed-in user, session expiry and profile. (online)",
+	Args:  cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		authStatus()
+	},
+}
+
 var LogoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Log out of your account. (online)",
@@ -82,19 +117,27 @@ var SetBioCmd = &cobra.Command{
 
 var ChangeNameCmd = &cobra.Command{
 	Use:   "changename [new name] [password]",
-	Short: "Change your profile name. (online)",
-	Args:  cobra.ExactArgs(2),
+	Short: "Change your profile name. Omitted arguments are prompted for. (online)",
+	Args:  cobra.MaximumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		changeName(args[0], args[1])
+		values := secretArgs(args,
+			secretField{title: "New name"},
+			secretField{title: "Password", secret: true})
+		changeName(values[0], values[1])
 	},
 }
 
 var ChangePassCmd = &cobra.Command{
 	Use:   "changepass [old password] [new password]",
-	Short: "Change your account password. (online)",
-	Args:  cobra.ExactArgs(2),
+	Short: "Change your account password. Omitted arguments are prompted for. (online)",
+	Long: `Change your account password. Omitted arguments are prompted for.
+With --password-stdin, the old and the new password are read from the first two lines of stdin.`,
+	Args: cobra.MaximumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		changePass(args[0], args[1])
+		values := secretArgs(args,
+			secretField{title: "Old password", secret: true},
+			secretField{title: "New password", secret: true, confirm: true})
+		changePass(values[0], values[1])
 	},
 }
 
@@ -118,10 +161,13 @@ var DeleteUserCmd = &cobra.Command{
 
 var ChangeEmailCmd = &cobra.Command{
 	Use:   "changemail [new email] [password]",
-	Short: "Change your account email. (online)",
-	Args:  cobra.ExactArgs(2),
+	Short: "Change your account email. Omitted arguments are prompted for. (online)",
+	Args:  cobra.MaximumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		changeEmail(args[0], args[1])
+		values := secretArgs(args,
+			secretField{title: "New email"},
+			secretField{title: "Password", secret: true})
+		changeEmail(values[0], values[1])
 	},
 }
 
@@ -6,87 +6,66 @@
 package user
 
 import (
-	"encoding/json"
 	"fmt"
+	"kncli/api"
+	problem "kncli/cmd/problems"
 	"kncli/internal"
-	"net/http"
 
-	"github.com/PuerkitoBio/goquery"
 	"github.com/charmbracelet/bubbles/table"
 )
 
 // get info about user
 func getUserBio(UserName string) string {
-	res, err := http.Get(fmt.Sprintf("https://kilonova.ro/profile/%s", UserName))
+	bio, err := internal.Client().UserBio(internal.Context(), UserName)
 	if err != nil {
 		internal.LogError(err)
 		return internal.ERROR
 	}
-	defer res.Body.Close()
-
-	doc, err := goquery.NewDocumentFromReader(res.Body)
-	if err != nil {
-		internal.LogError(err)
-		return internal.ERROR
-	}
-
-	bio := doc.Find("div.segment-panel.reset-list.statement-content.enhance-tables p").First().Text()
 
 	return bio
 }
 
 func userGetDetails(UserID, useCase string) bool {
-	var url string
+	var dataUser *api.User
+	var err error
 	if UserID == "me" {
-		url = internal.URL_SELF
+		dataUser, err = internal.Client().Self(internal.Context())
 	} else {
-		url = fmt.Sprintf(internal.URL_USER, UserID)
+		dataUser, err = internal.Client().GetUser(internal.Context(), UserID)
 	}
-
-	// Fetch user data
-	ResponseBody, err := internal.MakeGetRequest(url, nil, internal.RequestFormGuest)
 	if err != nil {
 		internal.LogError(fmt.Errorf("error fetching user details: %w", err))
 		return false
 	}
 
-	var dataUser UserDetailResponse
-	if err := json.Unmarshal(ResponseBody, &dataUser); err != nil {
-		internal.LogError(fmt.Errorf("error unmarshalling user data: %w", err))
-		return false
-	}
-
-	if dataUser.Data.DisplayName == "" {
-		dataUser.Data.DisplayName = "-"
+	if dataUser.DisplayName == "" {
+		dataUser.DisplayName = "-"
 	}
 
 	switch useCase {
 	case "isadmin":
-		return dataUser.Data.Admin
+		return dataUser.Admin
 	default:
-		printUserDetails(dataUser)
+		printUserDetails(*dataUser, UserID == "me")
 
 		return false
 	}
 }
 
 func userGetSolvedProblems(UserID string) {
-	var url string
+	var dataUser []api.SolvedProblem
+	var err error
 	if UserID == "me" {
-		url = internal.URL_SELF_PROBLEMS
+		dataUser, err = internal.Client().SelfSolvedProblems(internal.Context())
 	} else {
-		url = fmt.Sprintf(internal.URL_USER_PROBLEMS, UserID)
+		dataUser, err = internal.Client().SolvedProblems(internal.Context(), UserID)
 	}
-
-	ResponseBody, err := internal.MakeGetRequest(url, nil, internal.RequestFormGuest)
 	if err != nil {
 		internal.LogError(fmt.Errorf("error fetching solved problems: %w", err))
 		return
 	}
 
-	var dataUser UserSolvedProblems
-	if err := json.Unmarshal(ResponseBody, &dataUser); err != nil {
-		internal.LogError(fmt.Errorf("error unmarshalling solved problems: %w", err))
+	if internal.PrintOutput(dataUser) {
 		return
 	}
 
@@ -99,5 +78,31 @@ func userGetSolvedProblems(UserID string) {
 		{Title: "Score", Width: 7},
 	}
 
-	internal.RenderTable(Columns, Rows, 1)
+	internal.RenderTable(Columns, Rows, problem.OpenStatement(0, true))
+}
+
+// ProfileText returns the details of a user, as printed by 'user'.
+func ProfileText(UserID string) (string, error) {
+	dataUser, err := internal.Client().GetUser(internal.Context(), UserID)
+	if err != nil {
+		return "", fmt.Errorf("error fetching user details: %w", err)
+	}
+
+	if dataUser.DisplayName == "" {
+		dataUser.DisplayName = "-"
+	}
+
+	bio, err := internal.Client().UserBio(internal.Context(), dataUser.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return userDetailsText(*dataUser, bio, "")
+}
+
+// OpenProfile opens the details of the user whose ID is in the given column.
+func OpenProfile(column int) internal.RowOpener {
+	return internal.OpenText(func(row table.Row) (string, error) {
+		return ProfileText(row[column])
+	})
 }
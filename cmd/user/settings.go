@@ -6,126 +6,140 @@
 package user
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"kncli/internal"
-	u "net/url"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
 )
 
 func setUserBio(bio string) {
-	payload := map[string]string{"bio": bio}
-	resp, err := internal.PostJSON[internal.KilonovaResponse](internal.URL_SELF_SET_BIO, payload)
-	if err != nil {
-		internal.LogError(err)
+	if err := internal.Client().SetBio(internal.Context(), bio); err != nil {
+		internal.LogError(fmt.Errorf("failed to change bio: %w", err))
 		return
 	}
+	fmt.Println("Success! Bio changed!")
+}
 
-	if resp.Status == internal.SUCCESS {
-		fmt.Println("Success! Bio changed!")
-		return
+var settingsPasswordStdin bool
+
+// secretArgs returns the positional arguments of a settings command, asking
+// for those that were omitted. Passwords are read from stdin with
+// --password-stdin, or from masked prompts with the new password confirmed.
+func secretArgs(args []string, fields ...secretField) []string {
+	values := make([]string, len(fields))
+	copy(values, args)
+
+	if len(args) > 0 && len(args) == len(fields) {
+		fmt.Fprintln(os.Stderr, "Warning: passwords given as arguments end up in your shell history. Omit them to be asked instead.")
+		return values
+	}
+
+	var prompts []huh.Field
+	for i := len(args); i < len(fields); i++ {
+		field := fields[i]
+		value := &values[i]
+
+		if field.secret && settingsPasswordStdin {
+			secret, err := internal.ReadStdinSecret()
+			if err != nil {
+				internal.LogError(fmt.Errorf("failed to read %s: %w", strings.ToLower(field.title), err))
+			}
+			*value = secret
+			continue
+		}
+
+		input := huh.NewInput().Title(field.title + ":").Value(value).Validate(notEmpty)
+		if field.secret {
+			input = input.EchoMode(huh.EchoModePassword)
+		}
+		prompts = append(prompts, input)
+
+		if field.confirm {
+			var confirmation string
+			prompts = append(prompts, huh.NewInput().
+				Title("Confirm "+strings.ToLower(field.title)+":").
+				EchoMode(huh.EchoModePassword).
+				Value(&confirmation).
+				Validate(func(s string) error {
+					if s != *value {
+						return fmt.Errorf("the passwords don't match")
+					}
+					return nil
+				}))
+		}
+	}
+
+	if len(prompts) == 0 {
+		return values
+	}
+	if !internal.IsTerminal(os.Stdin) {
+		internal.LogError(fmt.Errorf("stdin is not a terminal: give the missing arguments, or use --password-stdin for passwords"))
+	}
+	if err := huh.NewForm(huh.NewGroup(prompts...)).Run(); err != nil {
+		internal.LogError(err)
 	}
-	fmt.Println("Error: Failed to change bio!")
+	return values
+}
 
+type secretField struct {
+	title   string
+	secret  bool
+	confirm bool
 }
 
-func changeName(newName, password string) {
-	payload := map[string]string{
-		"newName":  newName,
-		"password": password,
-	}
-	resp, err := internal.PostJSON[internal.KilonovaResponse](internal.URL_CHANGE_NAME, payload)
-	if err != nil {
-		internal.LogError(err)
-		return
+func notEmpty(s string) error {
+	if s == "" {
+		return fmt.Errorf("can't be empty")
 	}
+	return nil
+}
 
-	if resp.Status == internal.SUCCESS {
-		fmt.Println("Success! Name changed!")
+func changeName(newName, password string) {
+	if err := internal.Client().ChangeName(internal.Context(), newName, password); err != nil {
+		internal.LogError(fmt.Errorf("failed to change name: %w", err))
 		return
 	}
-	internal.LogError(fmt.Errorf("failed to change name"))
+	fmt.Println("Success! Name changed!")
 }
 
 func changePass(oldPass, newPass string) {
-	payload := map[string]string{
-		"old_password": oldPass,
-		"password":     newPass,
-	}
-	resp, err := internal.PostJSON[internal.KilonovaResponse](internal.URL_CHANGE_PASS, payload)
-	if err != nil {
-		internal.LogError(err)
+	if err := internal.Client().ChangePassword(internal.Context(), oldPass, newPass); err != nil {
+		internal.LogError(fmt.Errorf("failed to change password: %w", err))
 		return
 	}
-
-	if resp.Status == internal.SUCCESS {
-		fmt.Println("Success! Password changed! You'll need to login again.")
-		logout()
-		return
-	}
-	internal.LogError(fmt.Errorf("failed to change password"))
+	fmt.Println("Success! Password changed! You'll need to login again.")
+	logout()
 }
 
 func changeEmail(email, password string) {
-	formData := u.Values{}
-	formData.Set("email", email)
-	formData.Set("password", password)
-
-	ResponseBody, err := internal.MakePostRequest(internal.URL_CHANGE_EMAIL, bytes.NewBufferString(formData.Encode()), internal.RequestFormAuth)
-	if err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	var res internal.KilonovaResponse
-	if err := json.Unmarshal(ResponseBody, &res); err != nil {
-		internal.LogError(err)
+	if err := internal.Client().ChangeEmail(internal.Context(), email, password); err != nil {
+		internal.LogError(fmt.Errorf("failed to change email: %w", err))
 		return
 	}
-
-	if res.Status == internal.SUCCESS {
-		fmt.Println("Success! Email changed!")
-		return
-	}
-	internal.LogError(fmt.Errorf("failed to change email"))
+	fmt.Println("Success! Email changed!")
 }
 
 func resetPass(email string) {
-	if _, loggedIn := internal.ReadToken(); loggedIn {
+	if internal.SignedIn() {
 		fmt.Println("You must be logged out to reset your password.")
 		return
 	}
 
-	form := u.Values{}
-	form.Set("email", email)
-
-	ResponseBody, err := internal.MakePostRequest(internal.URL_CHANGE_PASS, bytes.NewBufferString(form.Encode()), internal.RequestFormAuth)
+	message, err := internal.Client().ResetPassword(internal.Context(), email)
 	if err != nil {
 		internal.LogError(err)
 		return
 	}
-
-	var res internal.KilonovaResponse
-	if err := json.Unmarshal(ResponseBody, &res); err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	fmt.Println(res.Data)
+	fmt.Println(message)
 }
 
 func resendEmail() {
-	ResponseBody, err := internal.MakePostRequest(internal.URL_RESEND_MAIL, nil, internal.RequestFormAuth)
+	message, err := internal.Client().ResendEmail(internal.Context())
 	if err != nil {
 		internal.LogError(err)
 		return
 	}
-
-	var res internal.KilonovaResponse
-	if err := json.Unmarshal(ResponseBody, &res); err != nil {
-		internal.LogError(err)
-		return
-	}
-
-	fmt.Println(res.Data)
+	fmt.Println(message)
 }
@@ -7,33 +7,26 @@ package user
 
 import (
 	"fmt"
+	"kncli/api"
 	utility "kncli/internal"
-	"os"
+	"strings"
 	"text/template"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/spf13/cobra"
 )
 
-type UserDetailResponse struct {
-	Data struct {
-		Id          int    `json:"id"`
-		Name        string `json:"name"`
-		Admin       bool   `json:"admin"`
-		Proposer    bool   `json:"proposer"`
-		DisplayName string `json:"display_name"`
-	} `json:"data"`
-}
+func init() {
+	SigninCmd.Flags().StringVarP(&signinUsername, "username", "u", "", "Username (env KNCLI_USERNAME).")
+	SigninCmd.Flags().BoolVar(&signinPasswordStdin, "password-stdin", false, "Read the password from stdin (otherwise env KNCLI_PASSWORD or a prompt).")
+	SigninCmd.Flags().BoolVar(&signinTokenStdin, "token-stdin", false, "Import an existing session token from stdin instead of signing in.")
 
-type UserSolvedProblems struct {
-	Data []struct {
-		ProblemId int     `json:"id"`
-		Name      string  `json:"name"`
-		Source    string  `json:"source_credits"`
-		Score     float64 `json:"score_scale"`
-	} `json:"data"`
-}
+	AuthCmd.AddCommand(AuthStatusCmd)
+
+	for _, cmd := range []*cobra.Command{ChangeNameCmd, ChangePassCmd, ChangeEmailCmd} {
+		cmd.Flags().BoolVar(&settingsPasswordStdin, "password-stdin", false, "Read passwords from stdin, one per line.")
+	}
 
-func init() {
 	SettingsCmd.AddCommand(ExtendSessionCmd)
 	SettingsCmd.AddCommand(SetBioCmd)
 	SettingsCmd.AddCommand(ChangeNameCmd)
@@ -47,17 +40,37 @@ func init() {
 
 }
 
-func printUserDetails(dataUser UserDetailResponse) {
-	userTemplate := `ID: {{.Id}}
+func printUserDetails(dataUser api.User, self bool) {
+	if utility.PrintOutput(dataUser) {
+		return
+	}
+
+	bio := getUserBio(dataUser.Name)
+
+	profile := ""
+	if self {
+		profile = utility.Profile()
+	}
+
+	text, err := userDetailsText(dataUser, bio, profile)
+	if err != nil {
+		utility.LogError(err)
+		return
+	}
+	fmt.Print(text)
+}
+
+func userDetailsText(dataUser api.User, bio, profile string) (string, error) {
+	userTemplate := `{{if .Profile}}Profile: {{.Profile}}
+{{end}}ID: {{.Id}}
 Name: {{.Name}}
 A.K.A: {{.DisplayName}}
 Bio: {{.Bio}}
 Admin: {{.Admin}}
 Proposer: {{.Proposer}}`
 
-	bio := getUserBio(dataUser.Data.Name)
-
 	userData := struct {
+		Profile     string
 		Id          int
 		Name        string
 		DisplayName string
@@ -65,29 +78,30 @@ Proposer: {{.Proposer}}`
 		Admin       bool
 		Proposer    bool
 	}{
-		Id:          dataUser.Data.Id,
-		Name:        dataUser.Data.Name,
-		DisplayName: dataUser.Data.DisplayName,
+		Profile:     profile,
+		Id:          dataUser.Id,
+		Name:        dataUser.Name,
+		DisplayName: dataUser.DisplayName,
 		Bio:         bio,
-		Admin:       dataUser.Data.Admin,
-		Proposer:    dataUser.Data.Proposer,
+		Admin:       dataUser.Admin,
+		Proposer:    dataUser.Proposer,
 	}
 
 	tmpl, err := template.New("userDetails").Parse(userTemplate)
 	if err != nil {
-		utility.LogError(fmt.Errorf("error parsing template: %w", err))
-		return
+		return "", fmt.Errorf("error parsing template: %w", err)
 	}
 
-	if err := tmpl.Execute(os.Stdout, userData); err != nil {
-		utility.LogError(fmt.Errorf("error executing template: %w", err))
-		return
+	var text strings.Builder
+	if err := tmpl.Execute(&text, userData); err != nil {
+		return "", fmt.Errorf("error executing template: %w", err)
 	}
+	return text.String(), nil
 }
 
-func prepareTableRows(dataUser UserSolvedProblems) []table.Row {
+func prepareTableRows(dataUser []api.SolvedProblem) []table.Row {
 	var rows []table.Row
-	for _, problem := range dataUser.Data {
+	for _, problem := range dataUser {
 		rows = append(rows, table.Row{
 			fmt.Sprintf("%d", problem.ProblemId),
 			problem.Name,
@@ -99,8 +113,7 @@ func prepareTableRows(dataUser UserSolvedProblems) []table.Row {
 }
 
 func isCurrentUserLoggedIn() bool {
-	_, hasToken := utility.ReadToken()
-	return hasToken
+	return utility.SignedIn()
 }
 
 func isAdmin(userId string) bool {
@@ -138,19 +151,18 @@ func deleteUser() {
 		return
 	}
 
-	ResponseBody, err := MakePostRequest(URL_CHANGE_EMAIL, nil, RequestFormAuth)
+	self, err := internal.Client().Self(internal.Context())
 	if err != nil {
-		LogError(err)
+		internal.LogError(err)
 		return
 	}
 
-	var dataKN KilonovaResponse
-	err = json.Unmarshal(ResponseBody, &dataKN)
+	message, err := internal.Client().DeleteUser(internal.Context(), strconv.Itoa(self.Id))
 	if err != nil {
-		LogError(err)
+		internal.LogError(err)
 		return
 	}
 
-	fmt.Println(dataKN.Data)
+	fmt.Println(message)
 }
 */
@@ -15,15 +15,14 @@ require (
 	github.com/charmbracelet/huh v0.6.0
 	github.com/charmbracelet/huh/spinner v0.0.0-20250331173942-310cd4a379ac
 	github.com/charmbracelet/lipgloss v1.1.0
-	github.com/eiannone/keyboard v0.0.0-20220611211555-0d226195f203
 	github.com/mattn/go-sqlite3 v1.14.27
 	github.com/spf13/cobra v1.9.1
+	golang.org/x/crypto v0.36.0
+	golang.org/x/term v0.30.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
-require (
-	github.com/mattn/go-colorable v0.1.13 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
-)
+require github.com/mattn/go-colorable v0.1.13 // indirect
 
 require (
 	github.com/alecthomas/chroma/v2 v2.14.0 // indirect
@@ -62,6 +61,5 @@ require (
 	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
-	golang.org/x/term v0.30.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 )
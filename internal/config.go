@@ -0,0 +1,192 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigKeys describes every key read from config.yaml.
+var ConfigKeys = map[string]string{
+	CONFIG_API_URL:             "base URL of the Kilonova instance",
+	CONFIG_RETRIES:             "retries for failed GET requests",
+	CONFIG_RATE_LIMIT:          "maximum requests per second, 0 to disable",
+	CONFIG_NO_CACHE:            "don't cache API responses (true/false)",
+	CONFIG_TTL_PROBLEM:         "lifetime of cached problem details, e.g. 12h",
+	CONFIG_TTL_LANGUAGES:       "lifetime of cached problem languages",
+	CONFIG_TTL_STATEMENT:       "lifetime of cached statements",
+	CONFIG_TIMEOUT:             "maximum duration of a request",
+	CONFIG_CONNECT_TIMEOUT:     "maximum time to connect to the server",
+	CONFIG_PROXY:               "proxy URL, overrides HTTPS_PROXY",
+	CONFIG_CA_FILE:             "PEM bundle of extra trusted root certificates",
+	CONFIG_DB_JOBS:             "parallel downloads of 'database create/refresh'",
+	CONFIG_TOKEN_PASSPHRASE:    "protect the session token with a passphrase (true/false)",
+	CONFIG_LANGUAGE:            "default language of 'submit' and 'init', e.g. cpp17",
+	CONFIG_SESSION_AUTO_EXTEND: "extend the session before it expires (true/false)",
+	CONFIG_SESSION_WINDOW:      "how long before the expiry the session is extended",
+	CONFIG_STATEMENT_LANGUAGE:  "statement language, RO or EN",
+	CONFIG_ONLINE:              "search and read statements online by default (true/false)",
+	CONFIG_PROJECT_CODEBLOCKS:  "create a Code::Blocks project with 'init' (true/false)",
+	CONFIG_PROJECT_CMAKE:       "create a CMake project with 'init' (true/false)",
+	CONFIG_SUBMISSIONS_USER:    "user of 'submission list': an ID, me or all",
+	CONFIG_SUBMISSIONS_PAGES:   "pages shown by 'submission list'",
+	CONFIG_OUTPUT:              "output of listing commands: table, json, csv, tsv or yaml",
+	CONFIG_NO_TUI:              "print tables and statements instead of opening a viewer (true/false)",
+}
+
+var configFile = ""
+
+// SetConfigFile makes the CLI read path instead of config.yaml in the config
+// dir, from the --config flag or KNCLI_CONFIG.
+func SetConfigFile(flagValue string) {
+	configFile = flagValue
+	if configFile == "" {
+		configFile = os.Getenv(ENV_CONFIG)
+	}
+}
+
+// ConfigFile returns the global config file.
+func ConfigFile() string {
+	if configFile != "" {
+		return configFile
+	}
+	return filepath.Join(GetConfigDir(), CONFIGFILENAME)
+}
+
+// ConfigFiles returns the config files read in order; later files override
+// earlier ones. The last one is where 'config set' writes.
+func ConfigFiles() []string {
+	files := []string{ConfigFile()}
+	if Profile() != DEFAULT_PROFILE {
+		files = append(files, filepath.Join(ProfileDir(Profile()), CONFIGFILENAME))
+	}
+	return files
+}
+
+// ReadConfig returns the settings of config.yaml, overridden by the
+// config.yaml of the active profile.
+func ReadConfig() (map[string]string, error) {
+	config := map[string]string{}
+
+	for _, file := range ConfigFiles() {
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", file, err)
+		}
+	}
+
+	return config, nil
+}
+
+func ConfigValue(key string) (string, bool) {
+	config, err := ReadConfig()
+	if err != nil {
+		LogError(err)
+		return "", false
+	}
+
+	value, ok := config[key]
+	return value, ok && value != ""
+}
+
+// Setting resolves a value with the precedence flag > environment > config file.
+func Setting(flagValue, envName, configKey string) (string, bool) {
+	if flagValue != "" {
+		return flagValue, true
+	}
+	if value := os.Getenv(envName); value != "" {
+		return value, true
+	}
+	return ConfigValue(configKey)
+}
+
+// FlagSetting resolves a flag of cmd with the precedence flag > environment >
+// config file, falling back to the flag's default.
+func FlagSetting(cmd *cobra.Command, name, envName, configKey string) string {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		LogError(fmt.Errorf("unknown flag %s", name))
+	}
+	if flag.Changed {
+		return flag.Value.String()
+	}
+	if value, ok := Setting("", envName, configKey); ok {
+		return value
+	}
+	return flag.DefValue
+}
+
+// BoolFlagSetting is FlagSetting for boolean flags.
+func BoolFlagSetting(cmd *cobra.Command, name, envName, configKey string) bool {
+	value := FlagSetting(cmd, name, envName, configKey)
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		LogError(fmt.Errorf("invalid value %q for %s: must be true or false", value, name))
+	}
+	return enabled
+}
+
+// ReadConfigFile returns the keys set in a single config file.
+func ReadConfigFile(file string) (map[string]string, error) {
+	config := map[string]string{}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", file, err)
+	}
+	return config, nil
+}
+
+// SetConfigValue stores a key in file, or removes it when value is empty.
+func SetConfigValue(file, key, value string) error {
+	config, err := ReadConfigFile(file)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		delete(config, key)
+	} else {
+		config[key] = value
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return WriteFileAtomic(file, data, 0644)
+}
+
+// SortedConfigKeys returns the known config keys in alphabetical order.
+func SortedConfigKeys() []string {
+	keys := make([]string, 0, len(ConfigKeys))
+	for key := range ConfigKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -5,64 +5,9 @@
 
 package internal
 
-type RequestType int
+import "time"
 
 const (
-	API_URL = "https://kilonova.ro/api/"
-
-	URL_LOGIN          = API_URL + "auth/login"
-	URL_LOGOUT         = API_URL + "auth/logout"
-	URL_EXTEND_SESSION = API_URL + "auth/extendSession"
-
-	URL_SEARCH  = API_URL + "problem/search"
-	URL_PROBLEM = API_URL + "problem/%s/"
-
-	URL_SELF          = API_URL + "user/self/"
-	URL_SELF_PROBLEMS = API_URL + "user/self/solvedProblems"
-	URL_SELF_SET_BIO  = API_URL + "user/self/setBio"
-
-	URL_CHANGE_EMAIL  = API_URL + "user/changeEmail"
-	URL_CHANGE_PASS   = API_URL + "user/changePassword"
-	URL_CHANGE_NAME   = API_URL + "user/updateName"
-	URL_RESEND_MAIL   = API_URL + "user/resendEmail"
-	URL_DELETE_USER   = API_URL + "user/moderation/deleteUser"
-	URL_USER          = API_URL + "user/byID/%s"
-	URL_USER_PROBLEMS = API_URL + "user/byID/%s/solvedProblems"
-
-	URL_LANGS_PB = API_URL + "problem/%s/languages"
-
-	URL_SUBMIT                     = API_URL + "submissions/submit"
-	URL_LATEST_SUBMISSION          = API_URL + "submissions/getByID?id=%s"
-	URL_SUBMISSION_LIST            = API_URL + "submissions/get?ascending=false&limit=50&offset=%d&ordering=id&problem_id=%s&user_id=%s"
-	URL_SUBMISSION_LIST_NO_FILTER  = API_URL + "submissions/get?ascending=false&limit=50&offset=%d&ordering=id"
-	URL_SUBMISSION_LIST_NO_PROBLEM = API_URL + "submissions/get?ascending=false&limit=50&offset=%d&ordering=id&user_id=%s"
-	URL_SUBMISSION_LIST_NO_USER    = API_URL + "submissions/get?ascending=false&limit=50&offset=%d&ordering=id&problem_id=%s"
-
-	URL_CONTEST                     = API_URL + "contest/%s"
-	URL_CONTEST_UPDATE              = API_URL + "contest/%s/update"
-	URL_CONTEST_CREATE              = API_URL + "contest/create"
-	URL_CONTEST_DELETE              = API_URL + "contest/%s/delete"
-	URL_CONTEST_REGISTER            = API_URL + "contest/%s/register"
-	URL_CONTEST_START               = API_URL + "contest/%s/startRegistration"
-	URL_CONTEST_ANNOUNCEMENTS       = API_URL + "contest/%s/announcements"
-	URL_CONTEST_CREATE_ANNOUNCEMENT = API_URL + "contest/%s/createAnnouncement"
-	URL_CONTEST_UPDATE_ANNOUNCEMENT = API_URL + "contest/%s/updateAnnouncement"
-	URL_CONTEST_DELETE_ANNOUNCEMENT = API_URL + "contest/%s/deleteAnnouncement"
-	URL_CONTEST_ASK_QUESTION        = API_URL + "contest/%s/askQuestion"
-	URL_CONTEST_RESPOND_QUESTION    = API_URL + "contest/%s/answerQuestion"
-	URL_CONTEST_YOUR_QUESTIONS      = API_URL + "contest/%s/questions"
-	URL_CONTEST_ALL_QUESTIONS       = API_URL + "contest/%s/allQuestions"
-	URL_CONTEST_UPDATE_PROBLEMS     = API_URL + "contest/%s/update/problems"
-	URL_CONTEST_PROBLEMS            = API_URL + "contest/%s/problems"
-	URL_CONTEST_LEADERBOARD         = API_URL + "contest/%s/leaderboard"
-
-	STAT_FILENAME_RO = "statement-ro.md"
-	STAT_FILENAME_EN = "statement-en.md"
-
-	URL_STATEMENT      = API_URL + "problem/%s/get/attachmentByName/%s"
-	URL_ASSETS         = "https://kilonova.ro/assets/problem/%s/problemArchive?tests=true&attachments=true&private_attachments=false&details=true&tags=true&editors=true&submissions=false&all_submissions=false"
-	URL_CONTEST_ASSETS = "https://kilonova.ro/assets/contest/%s/leaderboard.csv"
-
 	Version   = "v0.3.2"
 	UserAgent = "KilonovaCLIClient/" + Version
 
@@ -134,17 +79,6 @@ Credits: {{.Credits}} |
 
 	CMakeFilename = "CMakeLists.txt"
 
-	// Request Types
-
-	RequestNone          RequestType = iota
-	RequestFormAuth                  // 1
-	RequestJSON                      // 2
-	RequestFormGuest                 // 3
-	RequestDownloadZip               // 4
-	RequestInfo                      // 5
-	RequestMultipartForm             // 6
-	RequestDatabase                  // 7
-
 	RED   = "\033[31m"
 	WHITE = "\033[0m"
 
@@ -263,6 +197,103 @@ const (
 	CONFIGFOLDER     = ".config"
 	KNCLIFOLDER      = "kncli"
 	TOKENFILENAME    = "token.kn"
+	KEYFILENAME      = "key.kn"
 	PROBLEMSDATABASE = "problems.db"
 	LASTREFRESHDB    = "lastrefresh.kn"
+	CONFIGFILENAME   = "config.yaml"
+	CACHEFOLDER      = "cache"
+	PROFILESFOLDER   = "profiles"
+	PROFILEFILENAME  = "profile.kn"
+	SESSIONFILENAME  = "session.kn"
+	TEMPLATESFOLDER  = "templates"
+
+	TEMPLATEEXTENSION = ".tmpl"
+	DBBACKUPEXTENSION = ".bak"
+
+	// Separates the languages of the statements stored for a problem.
+	LANGUAGESEPARATOR = ","
+)
+
+// Output formats
+
+const (
+	OUTPUT_TABLE = "table"
+	OUTPUT_JSON  = "json"
+	OUTPUT_CSV   = "csv"
+	OUTPUT_TSV   = "tsv"
+	OUTPUT_YAML  = "yaml"
+)
+
+// Environment variables and config keys
+
+const (
+	ENV_API_URL    = "KNCLI_API_URL"
+	ENV_RETRIES    = "KNCLI_RETRIES"
+	ENV_RATE_LIMIT = "KNCLI_RATE_LIMIT"
+	ENV_NO_CACHE   = "KNCLI_NO_CACHE"
+	ENV_RECORD     = "KNCLI_RECORD"
+	ENV_REPLAY     = "KNCLI_REPLAY"
+	ENV_TRACE      = "KNCLI_TRACE"
+
+	ENV_TIMEOUT         = "KNCLI_TIMEOUT"
+	ENV_CONNECT_TIMEOUT = "KNCLI_CONNECT_TIMEOUT"
+	ENV_CA_FILE         = "KNCLI_CA_FILE"
+	ENV_DB_JOBS         = "KNCLI_DB_JOBS"
+	ENV_PASSPHRASE      = "KNCLI_PASSPHRASE"
+	ENV_PROFILE         = "KNCLI_PROFILE"
+	ENV_USERNAME        = "KNCLI_USERNAME"
+	ENV_PASSWORD        = "KNCLI_PASSWORD"
+
+	ENV_SESSION_AUTO_EXTEND = "KNCLI_SESSION_AUTO_EXTEND"
+
+	ENV_CONFIG             = "KNCLI_CONFIG"
+	ENV_LANGUAGE           = "KNCLI_LANGUAGE"
+	ENV_STATEMENT_LANGUAGE = "KNCLI_STATEMENT_LANGUAGE"
+	ENV_ONLINE             = "KNCLI_ONLINE"
+	ENV_OUTPUT             = "KNCLI_OUTPUT"
+	ENV_NO_TUI             = "KNCLI_NO_TUI"
+
+	CONFIG_API_URL       = "api_url"
+	CONFIG_RETRIES       = "retries"
+	CONFIG_RATE_LIMIT    = "rate_limit"
+	CONFIG_NO_CACHE      = "no_cache"
+	CONFIG_TTL_PROBLEM   = "cache_ttl_problem"
+	CONFIG_TTL_LANGUAGES = "cache_ttl_languages"
+	CONFIG_TTL_STATEMENT = "cache_ttl_statement"
+
+	CONFIG_TIMEOUT         = "timeout"
+	CONFIG_CONNECT_TIMEOUT = "connect_timeout"
+	CONFIG_PROXY           = "proxy"
+	CONFIG_CA_FILE         = "ca_file"
+	CONFIG_DB_JOBS         = "db_jobs"
+
+	CONFIG_TOKEN_PASSPHRASE = "token_passphrase"
+	CONFIG_LANGUAGE         = "language"
+
+	CONFIG_SESSION_AUTO_EXTEND = "session_auto_extend"
+	CONFIG_SESSION_WINDOW      = "session_extend_window"
+
+	CONFIG_STATEMENT_LANGUAGE = "statement_language"
+	CONFIG_ONLINE             = "online"
+	CONFIG_PROJECT_CODEBLOCKS = "project_codeblocks"
+	CONFIG_PROJECT_CMAKE      = "project_cmake"
+	CONFIG_SUBMISSIONS_USER   = "submissions_user"
+	CONFIG_SUBMISSIONS_PAGES  = "submissions_pages"
+	CONFIG_OUTPUT             = "output"
+	CONFIG_NO_TUI             = "no_tui"
+
+	DEFAULT_RATE_LIMIT = 10 // requests per second
+	DEFAULT_RATE_BURST = 5
+	DEFAULT_DB_JOBS    = 8
+	DEFAULT_PROFILE    = "default"
+
+	DEFAULT_STATEMENT_LANGUAGE = "RO"
+	DEFAULT_SUBMISSIONS_USER   = "all"
+	DEFAULT_SUBMISSIONS_PAGES  = 1
+
+	DEFAULT_SESSION_WINDOW = 7 * 24 * time.Hour
+	SESSION_WARNING        = 3 * 24 * time.Hour
 )
+
+// Exit code of commands cancelled by Ctrl-C or SIGTERM, as in shells.
+const EXIT_INTERRUPTED = 130
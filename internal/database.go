@@ -19,15 +19,7 @@ func RefreshOrNotDB() bool {
 	layout := time.RFC3339
 
 	if !FileExists(LASTREFRESHDB) {
-		file, err := os.Create(filePath)
-		if err != nil {
-			LogError(err)
-			return false
-		}
-		defer file.Close()
-
-		_, err = file.WriteString(currentTime.Format(layout))
-		if err != nil {
+		if err := WriteFileAtomic(filePath, []byte(currentTime.Format(layout)), 0644); err != nil {
 			LogError(err)
 			return false
 		}
@@ -56,7 +48,25 @@ func DBExists() bool {
 	return FileExists(PROBLEMSDATABASE)
 }
 
+// dbMigrated avoids checking the schema version every time the database is opened.
+var dbMigrated = false
+
+// DBOpen opens the problems database, applying any pending migration.
 func DBOpen() *sql.DB {
+	db := DBOpenRaw()
+	if !dbMigrated {
+		if _, err := MigrateDB(db); err != nil {
+			DBClose(db)
+			LogError(err)
+			return nil
+		}
+		dbMigrated = true
+	}
+	return db
+}
+
+// DBOpenRaw opens the problems database as it is, without migrating it.
+func DBOpenRaw() *sql.DB {
 	DBFilename := filepath.Join(GetConfigDir(), PROBLEMSDATABASE)
 	db, err := sql.Open("sqlite3", DBFilename)
 	if err != nil {
@@ -6,66 +6,239 @@
 package internal
 
 import (
-	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
-	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Tokens are stored as "v2:<scheme>:<salt>:<nonce+ciphertext>" and sealed with
+// AES-256-GCM. The "key" scheme uses a random key generated on first use and
+// kept in key.kn (mode 0600); the "scrypt" scheme derives the key from a
+// passphrase, for machines shared by several people.
+
+const (
+	tokenFormat    = "v2"
+	schemeKeyFile  = "key"
+	schemeScrypt   = "scrypt"
+	tokenAD        = "kncli-token"
+	keySize        = 32
+	saltSize       = 16
+	scryptN        = 1 << 15
+	scryptR        = 8
+	scryptP        = 1
+	passphraseHint = "set " + ENV_PASSPHRASE + " or run in a terminal"
 )
 
-var key = []byte("ThIsis32bYteKeyForAES256exAmple!")
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted token")
+
+// Key of the CBC format used before v2, only kept to migrate old tokens.
+var legacyKey = []byte("ThIsis32bYteKeyForAES256exAmple!")
+
+// installKey returns the per-install key, creating it on first use.
+func installKey() ([]byte, error) {
+	keyFile := filepath.Join(GetConfigDir(), KEYFILENAME)
+
+	data, err := os.ReadFile(keyFile)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("invalid key file %s", keyFile)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
 
-func pad(data []byte) []byte {
-	padLen := aes.BlockSize - len(data)%aes.BlockSize
-	return append(data, bytes.Repeat([]byte{byte(padLen)}, padLen)...)
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := WriteFileAtomic(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+	return key, nil
 }
 
-func unpad(data []byte) ([]byte, error) {
-	paddingLen := int(data[len(data)-1])
-	if paddingLen > aes.BlockSize || paddingLen == 0 {
-		return nil, fmt.Errorf("invalid padding")
+// UsePassphrase reports whether new tokens are protected by a passphrase
+// instead of the install key.
+func UsePassphrase() bool {
+	if os.Getenv(ENV_PASSPHRASE) != "" {
+		return true
 	}
-	return data[:len(data)-paddingLen], nil
+	value, _ := ConfigValue(CONFIG_TOKEN_PASSPHRASE)
+	return value == BOOLTRUE
 }
 
-func Encrypt(text string) (string, error) {
-	plain := pad([]byte(text))
+func passphrase() (string, error) {
+	if value := os.Getenv(ENV_PASSPHRASE); value != "" {
+		return value, nil
+	}
+	if !IsTerminal(os.Stdin) {
+		return "", fmt.Errorf("the session token is protected by a passphrase: %s", passphraseHint)
+	}
 
-	block, err := aes.NewCipher(key)
+	var value string
+	err := huh.NewInput().
+		Title("Token passphrase:").
+		EchoMode(huh.EchoModePassword).
+		Value(&value).
+		Run()
 	if err != nil {
 		return "", err
 	}
+	if value == "" {
+		return "", fmt.Errorf("empty passphrase")
+	}
+	return value, nil
+}
+
+func scryptKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+}
+
+func seal(key, plain []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plain, []byte(tokenAD)), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
 
-	iv := make([]byte, aes.BlockSize)
-	_, err = io.ReadFull(rand.Reader, iv)
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(tokenAD))
 	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plain, nil
+}
+
+// Encrypt seals a token with the install key, or with a passphrase when
+// UsePassphrase is set.
+func Encrypt(text string) (string, error) {
+	scheme := schemeKeyFile
+	var salt, key []byte
+	var err error
+
+	if UsePassphrase() {
+		scheme = schemeScrypt
+		salt = make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+
+		secret, err := passphrase()
+		if err != nil {
+			return "", err
+		}
+		key, err = scryptKey(secret, salt)
+		if err != nil {
+			return "", err
+		}
+	} else if key, err = installKey(); err != nil {
 		return "", err
 	}
 
-	cipherText := make([]byte, len(plain))
-	cbc := cipher.NewCBCEncrypter(block, iv)
-	cbc.CryptBlocks(cipherText, plain)
+	sealed, err := seal(key, []byte(text))
+	if err != nil {
+		return "", err
+	}
 
-	final := append(iv, cipherText...)
-	return base64.StdEncoding.EncodeToString(final), nil
+	return strings.Join([]string{
+		tokenFormat,
+		scheme,
+		base64.StdEncoding.EncodeToString(salt),
+		base64.StdEncoding.EncodeToString(sealed),
+	}, ":"), nil
 }
 
-func Decrypt(encrypted string) (string, error) {
+// Decrypt opens a token written by Encrypt. Tokens in the old CBC format are
+// still accepted; legacy reports that the token should be re-encrypted.
+func Decrypt(encrypted string) (text string, legacy bool, err error) {
+	if !strings.HasPrefix(encrypted, tokenFormat+":") {
+		text, err = decryptLegacy(encrypted)
+		return text, true, err
+	}
 
+	parts := strings.Split(encrypted, ":")
+	if len(parts) != 4 {
+		return "", false, fmt.Errorf("malformed token")
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", false, fmt.Errorf("malformed token: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", false, fmt.Errorf("malformed token: %w", err)
+	}
+
+	var key []byte
+	switch parts[1] {
+	case schemeKeyFile:
+		key, err = installKey()
+	case schemeScrypt:
+		var secret string
+		if secret, err = passphrase(); err == nil {
+			key, err = scryptKey(secret, salt)
+		}
+	default:
+		err = fmt.Errorf("unknown token scheme %q", parts[1])
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	plain, err := open(key, sealed)
+	if err != nil {
+		return "", false, err
+	}
+	return string(plain), false, nil
+}
+
+func decryptLegacy(encrypted string) (string, error) {
 	data, err := base64.StdEncoding.DecodeString(encrypted)
 	if err != nil {
 		return "", err
 	}
 
-	if len(data) < aes.BlockSize {
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
 		return "", fmt.Errorf("ciphertext too short")
 	}
 	iv := data[:aes.BlockSize]
 	cipherText := data[aes.BlockSize:]
 
-	block, err := aes.NewCipher(key)
+	block, err := aes.NewCipher(legacyKey)
 	if err != nil {
 		return "", err
 	}
@@ -74,10 +247,9 @@ func Decrypt(encrypted string) (string, error) {
 	cbc := cipher.NewCBCDecrypter(block, iv)
 	cbc.CryptBlocks(plain, cipherText)
 
-	plain, err = unpad(plain)
-	if err != nil {
-		return "", err
+	paddingLen := int(plain[len(plain)-1])
+	if paddingLen > aes.BlockSize || paddingLen == 0 {
+		return "", fmt.Errorf("invalid padding")
 	}
-
-	return string(plain), nil
+	return string(plain[:len(plain)-paddingLen]), nil
 }
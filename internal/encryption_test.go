@@ -0,0 +1,154 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTempConfig points the config dir at an empty temporary home.
+func useTempConfig(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(ENV_PASSPHRASE, "")
+	return GetConfigDir()
+}
+
+// legacyToken encrypts text like the CBC format used before v2.
+func legacyToken(t *testing.T, text string) string {
+	t.Helper()
+	block, err := aes.NewCipher(legacyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	padding := aes.BlockSize - len(text)%aes.BlockSize
+	plain := append([]byte(text), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	data := make([]byte, aes.BlockSize+len(plain))
+	copy(data, "0123456789abcdef")
+	cipher.NewCBCEncrypter(block, data[:aes.BlockSize]).CryptBlocks(data[aes.BlockSize:], plain)
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	tests := []struct {
+		name       string
+		passphrase string
+		scheme     string
+	}{
+		{"install key", "", schemeKeyFile},
+		{"passphrase", "correct horse", schemeScrypt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempConfig(t)
+			t.Setenv(ENV_PASSPHRASE, tt.passphrase)
+
+			encrypted, err := Encrypt("session-token")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(encrypted, tokenFormat+":"+tt.scheme+":") {
+				t.Errorf("Encrypt = %q, want the %s scheme", encrypted, tt.scheme)
+			}
+			if strings.Contains(encrypted, "session-token") {
+				t.Error("the token is stored in clear")
+			}
+
+			again, err := Encrypt("session-token")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again == encrypted {
+				t.Error("two encryptions of the same token are identical")
+			}
+
+			text, legacy, err := Decrypt(encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text != "session-token" || legacy {
+				t.Errorf("Decrypt = %q, %v, want %q, false", text, legacy, "session-token")
+			}
+		})
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	useTempConfig(t)
+	t.Setenv(ENV_PASSPHRASE, "correct horse")
+	withPassphrase, err := Encrypt("session-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ENV_PASSPHRASE, "")
+	withKey, err := Encrypt("session-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(withKey, ":")
+	sealed, _ := base64.StdEncoding.DecodeString(parts[3])
+	sealed[len(sealed)-1] ^= 1
+	tampered := strings.Join([]string{parts[0], parts[1], parts[2], base64.StdEncoding.EncodeToString(sealed)}, ":")
+
+	tests := []struct {
+		name       string
+		encrypted  string
+		passphrase string
+		wantErr    error
+	}{
+		{"wrong passphrase", withPassphrase, "wrong horse", ErrWrongPassphrase},
+		{"tampered ciphertext", tampered, "", ErrWrongPassphrase},
+		{"malformed", tokenFormat + ":" + schemeKeyFile + ":", "", nil},
+		{"unknown scheme", tokenFormat + ":rot13::AAAA", "", nil},
+		{"invalid legacy token", "not base64!", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ENV_PASSPHRASE, tt.passphrase)
+			_, _, err := Decrypt(tt.encrypted)
+			if err == nil {
+				t.Fatal("Decrypt succeeded")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decrypt error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Without key.kn a new key is generated, which can't open the old token.
+	if err := os.Remove(filepath.Join(GetConfigDir(), KEYFILENAME)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Decrypt(withKey); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Decrypt without the key file = %v, want %v", err, ErrWrongPassphrase)
+	}
+}
+
+func TestDecryptLegacy(t *testing.T) {
+	useTempConfig(t)
+
+	for _, token := range []string{"a", "exactly-16-bytes", "a-longer-session-token-0123456789"} {
+		text, legacy, err := Decrypt(legacyToken(t, token))
+		if err != nil {
+			t.Fatalf("Decrypt(%q): %v", token, err)
+		}
+		if text != token || !legacy {
+			t.Errorf("Decrypt = %q, %v, want %q, true", text, legacy, token)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --format and --template print results with a user template instead of the
+// built-in output. Templates see the API structs, e.g. '{{.Id}} {{.Score}}',
+// and are applied to each element of a list.
+
+var outputTemplate *template.Template
+
+var colors = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
+// TemplateFuncs are the helpers available in output templates.
+var TemplateFuncs = template.FuncMap{
+	// date "2006-01-02 15:04" .CreatedAt
+	"date": func(layout string, value any) (string, error) {
+		t, err := templateTime(value)
+		if err != nil {
+			return "", err
+		}
+		return t.Format(layout), nil
+	},
+	// ago .CreatedAt prints e.g. "2d 5h"
+	"ago": func(value any) (string, error) {
+		t, err := templateTime(value)
+		if err != nil {
+			return "", err
+		}
+		return FormatDuration(time.Since(t)), nil
+	},
+	// pad 10 .Name aligns left, padLeft 10 .Score aligns right
+	"pad": func(width int, value any) string {
+		text := fmt.Sprint(value)
+		return text + strings.Repeat(" ", max(width-lipgloss.Width(text), 0))
+	},
+	"padLeft": func(width int, value any) string {
+		text := fmt.Sprint(value)
+		return strings.Repeat(" ", max(width-lipgloss.Width(text), 0)) + text
+	},
+	"trunc": func(width int, value any) string {
+		runes := []rune(fmt.Sprint(value))
+		if len(runes) <= width {
+			return string(runes)
+		}
+		return string(runes[:max(width-1, 0)]) + "…"
+	},
+	// color "green" .Score takes a name, an ANSI number or "#rrggbb";
+	// colours are dropped when stdout isn't a terminal.
+	"color": func(name string, value any) string {
+		if code, ok := colors[strings.ToLower(name)]; ok {
+			name = code
+		}
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(name)).Render(fmt.Sprint(value))
+	},
+	"bold": func(value any) string {
+		return lipgloss.NewStyle().Bold(true).Render(fmt.Sprint(value))
+	},
+	"upper": func(value any) string { return strings.ToUpper(fmt.Sprint(value)) },
+	"lower": func(value any) string { return strings.ToLower(fmt.Sprint(value)) },
+	"join": func(separator string, values any) string {
+		list := reflect.ValueOf(values)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			return fmt.Sprint(values)
+		}
+		parts := make([]string, list.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(list.Index(i).Interface())
+		}
+		return strings.Join(parts, separator)
+	},
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"base64decode": func(value string) (string, error) {
+		data, err := base64.StdEncoding.DecodeString(value)
+		return string(data), err
+	},
+	// default "-" .Source replaces empty values
+	"default": func(fallback, value any) any {
+		if value == nil || reflect.ValueOf(value).IsZero() {
+			return fallback
+		}
+		return value
+	},
+}
+
+func templateTime(value any) (time.Time, error) {
+	switch value := value.(type) {
+	case time.Time:
+		return value, nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %v", value)
+}
+
+// SetOutputTemplate parses the template given with --format, or the file
+// given with --template. A template name without a path is looked up in the
+// templates folder of the config dir, e.g. "report" is templates/report.tmpl.
+func SetOutputTemplate(format, file string) error {
+	if format != "" && file != "" {
+		return fmt.Errorf("--format and --template can't be used together")
+	}
+
+	name := "format"
+	if file != "" {
+		path, err := templatePath(file)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		name, format = filepath.Base(path), string(data)
+	}
+	if format == "" {
+		return nil
+	}
+
+	tmpl, err := template.New(name).Funcs(TemplateFuncs).Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	outputTemplate = tmpl
+	return nil
+}
+
+func templatePath(file string) (string, error) {
+	if _, err := os.Stat(file); err == nil {
+		return file, nil
+	}
+	if !strings.ContainsRune(file, os.PathSeparator) {
+		name := file
+		if filepath.Ext(name) == "" {
+			name += TEMPLATEEXTENSION
+		}
+		path := filepath.Join(GetConfigDir(), TEMPLATESFOLDER, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("template %q not found", file)
+}
+
+// writeTemplate executes the output template on data, or on each element
+// when data is a list, ending every result with a newline.
+func writeTemplate(w io.Writer, data any) error {
+	items := []any{data}
+	if value := reflect.ValueOf(data); value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		items = make([]any, value.Len())
+		for i := range items {
+			items[i] = value.Index(i).Interface()
+		}
+	}
+
+	for _, item := range items {
+		var out bytes.Buffer
+		if err := outputTemplate.Execute(&out, item); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+			out.WriteByte('\n')
+		}
+		if _, err := out.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The full-text index over names, credits and decoded statements lives in the
+// statements_fts FTS5 table, with a row per stored statement. It isn't part of
+// the versioned schema: FTS5 is only compiled into go-sqlite3 with the
+// sqlite_fts5 build tag, so the index is created and brought up to date when a
+// text search needs it.
+
+// ErrNoFTS5 is returned when SQLite was built without FTS5.
+var ErrNoFTS5 = errors.New("SQLite was built without FTS5 (build kncli with -tags sqlite_fts5)")
+
+// SyncSearchIndex creates the full-text index if needed and indexes the rows
+// stored or rewritten since it was last updated, which have indexed = 0.
+func SyncSearchIndex(db *sql.DB) error {
+	var exists int
+	if err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE name = 'statements_fts';`).Scan(&exists); err != nil {
+		return err
+	}
+
+	// problems_fts indexed a single statement per problem.
+	_, err := db.Exec(`DROP TABLE IF EXISTS problems_fts;
+CREATE VIRTUAL TABLE IF NOT EXISTS statements_fts
+USING fts5(name, credits, statement, problem_id UNINDEXED, language UNINDEXED,
+tokenize = 'unicode61 remove_diacritics 2');`)
+	if err != nil {
+		if err = ftsError(err); err == ErrNoFTS5 {
+			return err
+		}
+		return fmt.Errorf("failed to create the search index: %w", err)
+	}
+	if exists == 0 {
+		// A new index starts empty, whatever the rows say.
+		if _, err := db.Exec(`UPDATE problems SET indexed = 0; UPDATE statements SET indexed = 0;`); err != nil {
+			return err
+		}
+	}
+
+	// Statements are indexed under their rowid. Problems without statements
+	// per language, either none or the single one stored by older versions,
+	// are indexed under -id, and dropped once a refresh stores their statements.
+	_, err = db.Exec(`DELETE FROM statements_fts
+WHERE rowid < 0 AND -rowid IN (SELECT id FROM problems WHERE languages <> '');`)
+	if err != nil {
+		return ftsError(err)
+	}
+
+	type pending struct {
+		rowid, id                          int
+		name, credits, language, statement sql.NullString
+	}
+
+	// Read everything first: SQLite can't commit while the query is open. A
+	// renamed problem is indexed again with all its statements.
+	rows, err := db.Query(`SELECT s.rowid, p.id, p.name, p.credits, s.language, s.statement
+FROM statements s JOIN problems p ON p.id = s.problem_id
+WHERE s.indexed = 0 OR p.indexed = 0
+UNION ALL
+SELECT -p.id, p.id, p.name, p.credits, '', p.statement
+FROM problems p
+WHERE (p.languages IS NULL OR p.languages = '') AND p.indexed = 0;`)
+	if err != nil {
+		return ftsError(err)
+	}
+	var statements []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.rowid, &p.id, &p.name, &p.credits, &p.language, &p.statement); err != nil {
+			rows.Close()
+			return err
+		}
+		statements = append(statements, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(statements) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, p := range statements {
+		_, err := tx.Exec(`DELETE FROM statements_fts WHERE rowid = ?;`, p.rowid)
+		if err == nil {
+			_, err = tx.Exec(`INSERT INTO statements_fts (rowid, name, credits, statement, problem_id, language)
+VALUES (?, ?, ?, ?, ?, ?);`,
+				p.rowid, p.name.String, p.credits.String, IndexedStatement(p.statement.String), p.id, p.language.String)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to index problem %d: %w", p.id, err)
+		}
+	}
+	if _, err := tx.Exec(`UPDATE problems SET indexed = 1 WHERE indexed = 0;
+UPDATE statements SET indexed = 1 WHERE indexed = 0;`); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// IndexedStatement returns the searchable text of a stored statement.
+func IndexedStatement(stored string) string {
+	if stored == "" || stored == NOLANG {
+		return ""
+	}
+	text, err := DecodeBase64Text(stored)
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+// ftsError returns ErrNoFTS5 for the errors of builds without FTS5, which
+// also fail on an index created by a build with it.
+func ftsError(err error) error {
+	if strings.Contains(err.Error(), "no such module: fts5") {
+		return ErrNoFTS5
+	}
+	return err
+}
@@ -0,0 +1,12 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+//go:build !(sqlite_fts5 || fts5)
+
+package internal
+
+// FTS5 is false without the sqlite_fts5 build tag: search --text falls back
+// to a plain word search.
+const FTS5 = false
@@ -0,0 +1,13 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+//go:build sqlite_fts5 || fts5
+
+package internal
+
+// FTS5 reports whether go-sqlite3 was compiled with the FTS5 module, which
+// search --text needs for its query syntax and ranking. It uses the same
+// build tags as go-sqlite3.
+const FTS5 = true
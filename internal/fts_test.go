@@ -0,0 +1,154 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIndexedStatement(t *testing.T) {
+	tests := []struct {
+		stored string
+		want   string
+	}{
+		{"", ""},
+		{NOLANG, ""},
+		{"not base64!", ""},
+		{"U3VtYSBhIGRvdcSDIG51bWVyZQ==", "Suma a două numere"},
+	}
+	for _, tt := range tests {
+		if got := IndexedStatement(tt.stored); got != tt.want {
+			t.Errorf("IndexedStatement(%q) = %q, want %q", tt.stored, got, tt.want)
+		}
+	}
+}
+
+func TestFTSError(t *testing.T) {
+	if err := ftsError(errors.New("no such module: fts5")); err != ErrNoFTS5 {
+		t.Errorf("ftsError = %v, want ErrNoFTS5", err)
+	}
+	other := errors.New("database is locked")
+	if err := ftsError(other); err != other {
+		t.Errorf("ftsError = %v, want the error unchanged", err)
+	}
+}
+
+func TestFTS5MatchesBuild(t *testing.T) {
+	useTempConfig(t)
+	db := openTestDB(t, filepath.Join(t.TempDir(), "test.db"))
+	if _, err := MigrateDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	err := SyncSearchIndex(db)
+	if err != nil && !errors.Is(err, ErrNoFTS5) {
+		t.Fatal(err)
+	}
+	if available := err == nil; available != FTS5 {
+		t.Errorf("FTS5 = %v, but SQLite has FTS5: %v", FTS5, available)
+	}
+}
+
+// searchIDs returns the problems matching query, skipping the test in builds
+// without FTS5.
+func searchIDs(t *testing.T, db *sql.DB, query string) []int {
+	t.Helper()
+	if err := SyncSearchIndex(db); errors.Is(err, ErrNoFTS5) {
+		t.Skip("SQLite was built without FTS5, run with -tags sqlite_fts5")
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT DISTINCT problem_id FROM statements_fts WHERE statements_fts MATCH ? ORDER BY problem_id;`, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestSyncSearchIndex(t *testing.T) {
+	useTempConfig(t)
+	db := openTestDB(t, filepath.Join(t.TempDir(), "test.db"))
+	if _, err := MigrateDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	encode := func(text string) string {
+		encoded, _ := EncodeBase64Text(text)
+		return encoded
+	}
+	exec := func(query string, args ...any) {
+		t.Helper()
+		if _, err := db.Exec(query, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exec(`INSERT INTO problems (id, name, credits, languages) VALUES (1, 'sum', 'ONI', 'RO,EN'), (2, 'arbore', 'OJI', '');`)
+	exec(`INSERT INTO statements (problem_id, language, attachment, statement) VALUES (1, 'RO', 'statement-ro.md', ?), (1, 'EN', 'statement-en.md', ?);`,
+		encode("Calculați suma"), encode("Compute the sum"))
+	// Stored by an older version, with a single statement.
+	exec(`INSERT INTO problems (id, name, statement) VALUES (3, 'legacy', ?);`, encode("interval tree"))
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"calculati", []int{1}}, // diacritics are ignored
+		{"compute", []int{1}},
+		{"arbore", []int{2}},
+		{"interval", []int{3}},
+		{"OJI OR ONI", []int{1, 2}},
+	}
+	for _, tt := range tests {
+		if got := searchIDs(t, db, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("search %q = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	// A refresh rewrites rows and clears indexed.
+	exec(`UPDATE statements SET statement = ?, indexed = 0 WHERE problem_id = 1 AND language = 'EN';`, encode("Add two numbers"))
+	exec(`UPDATE problems SET name = 'segment', indexed = 0 WHERE id = 2;`)
+	exec(`UPDATE problems SET languages = 'RO', statement = NULL, indexed = 0 WHERE id = 3;`)
+	exec(`INSERT INTO statements (problem_id, language, attachment, statement) VALUES (3, 'RO', 'statement-ro.md', ?);`, encode("arbore de intervale"))
+
+	tests = []struct {
+		query string
+		want  []int
+	}{
+		{"compute", nil},
+		{"numbers", []int{1}},
+		{"arbore", []int{3}},
+		{"segment", []int{2}},
+		{"tree", nil},
+	}
+	for _, tt := range tests {
+		if got := searchIDs(t, db, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("after the refresh, search %q = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	var rows int
+	if err := db.QueryRow(`SELECT count(*) FROM statements_fts;`).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 4 {
+		t.Errorf("the index has %d rows, want 4", rows)
+	}
+}
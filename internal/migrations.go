@@ -0,0 +1,141 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The schema of the problems database is versioned with PRAGMA user_version.
+// Migrations are applied in order, each in its own transaction, when the
+// database is opened. Never edit or reorder released migrations: append a new
+// one instead.
+
+type Migration struct {
+	Description string
+	// Destructive migrations drop or rebuild data, so the database file is
+	// backed up before they run.
+	Destructive bool
+	Up          func(tx *sql.Tx) error
+}
+
+// Migrations[i] upgrades the schema from version i to version i+1.
+var Migrations = []Migration{
+	{
+		// Databases created before migrations existed already have this table.
+		Description: "create the problems table",
+		Up: execSQL(`CREATE TABLE IF NOT EXISTS problems (
+id INTEGER PRIMARY KEY,
+name TEXT,
+timelimit FLOAT,
+memorylimit INTEGER,
+sourcesize INTEGER,
+credits TEXT,
+statement TEXT
+);`),
+	},
+	{
+		// problems.statement keeps the single statement stored by older
+		// versions, in an unknown language, until the next refresh fills
+		// statements and sets languages.
+		Description: "store a statement per language",
+		Up: execSQL(`CREATE TABLE statements (
+problem_id INTEGER NOT NULL,
+language TEXT NOT NULL,
+attachment TEXT NOT NULL,
+statement TEXT NOT NULL,
+PRIMARY KEY (problem_id, language)
+);
+ALTER TABLE problems ADD COLUMN languages TEXT;`),
+	},
+	{
+		// Cleared whenever a refresh rewrites a row, so that the full-text
+		// index picks up the change.
+		Description: "track the rows to search-index again",
+		Up: execSQL(`ALTER TABLE problems ADD COLUMN indexed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE statements ADD COLUMN indexed INTEGER NOT NULL DEFAULT 0;`),
+	},
+}
+
+func execSQL(query string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+// SchemaVersion returns the number of migrations applied to db.
+func SchemaVersion(db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version;`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read the database version: %w", err)
+	}
+	return version, nil
+}
+
+// MigrateDB applies the pending migrations and returns how many were applied.
+func MigrateDB(db *sql.DB) (int, error) {
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return 0, err
+	}
+	if version > len(Migrations) {
+		return 0, fmt.Errorf("the database has schema version %d, newer than this version of kncli supports (%d); update kncli", version, len(Migrations))
+	}
+
+	applied := 0
+	for ; version < len(Migrations); version++ {
+		migration := Migrations[version]
+		if migration.Destructive {
+			if err := backupDB(db, version); err != nil {
+				return applied, err
+			}
+		}
+		if err := applyMigration(db, migration, version+1); err != nil {
+			return applied, fmt.Errorf("migration %d (%s) failed: %w", version+1, migration.Description, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+func applyMigration(db *sql.DB, migration Migration, version int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := migration.Up(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	// user_version is part of the transaction, so a failed migration leaves
+	// the version unchanged.
+	if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d;`, version)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DBBackupPath returns the backup taken before upgrading from version.
+func DBBackupPath(version int) string {
+	return filepath.Join(GetConfigDir(), fmt.Sprintf("%s.v%d%s", PROBLEMSDATABASE, version, DBBACKUPEXTENSION))
+}
+
+func backupDB(db *sql.DB, version int) error {
+	backup := DBBackupPath(version)
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// VACUUM INTO writes a consistent copy, even while the database is open.
+	if _, err := db.Exec(`VACUUM INTO ?;`, backup); err != nil {
+		return fmt.Errorf("failed to back up the database to %q: %w", backup, err)
+	}
+	return nil
+}
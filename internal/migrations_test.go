@@ -0,0 +1,194 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var count int
+	if err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE name = ?;`, name).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestMigrateDB(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name        string
+		migrations  []Migration
+		start       int
+		wantApplied int
+		wantVersion int
+		wantTables  []string
+		missing     []string
+		wantErr     bool
+	}{
+		{
+			name: "applies pending migrations in order",
+			migrations: []Migration{
+				{Description: "a", Up: execSQL(`CREATE TABLE a (id INTEGER);`)},
+				{Description: "b", Up: execSQL(`ALTER TABLE a ADD COLUMN b TEXT; CREATE TABLE b (id INTEGER);`)},
+			},
+			wantApplied: 2,
+			wantVersion: 2,
+			wantTables:  []string{"a", "b"},
+		},
+		{
+			name: "skips applied migrations",
+			migrations: []Migration{
+				{Description: "a", Up: func(*sql.Tx) error { return errFailed }},
+				{Description: "b", Up: execSQL(`CREATE TABLE b (id INTEGER);`)},
+			},
+			start:       1,
+			wantApplied: 1,
+			wantVersion: 2,
+			wantTables:  []string{"b"},
+		},
+		{
+			name: "a failed migration is rolled back",
+			migrations: []Migration{
+				{Description: "a", Up: execSQL(`CREATE TABLE a (id INTEGER);`)},
+				{Description: "b", Up: func(tx *sql.Tx) error {
+					if _, err := tx.Exec(`CREATE TABLE b (id INTEGER);`); err != nil {
+						return err
+					}
+					return errFailed
+				}},
+				{Description: "c", Up: execSQL(`CREATE TABLE c (id INTEGER);`)},
+			},
+			wantApplied: 1,
+			wantVersion: 1,
+			wantTables:  []string{"a"},
+			missing:     []string{"b", "c"},
+			wantErr:     true,
+		},
+		{
+			name:        "refuses newer databases",
+			migrations:  []Migration{{Description: "a", Up: execSQL(`CREATE TABLE a (id INTEGER);`)}},
+			start:       5,
+			wantVersion: 5,
+			missing:     []string{"a"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempConfig(t)
+			saved := Migrations
+			Migrations = tt.migrations
+			t.Cleanup(func() { Migrations = saved })
+
+			db := openTestDB(t, filepath.Join(t.TempDir(), "test.db"))
+			if _, err := db.Exec(fmt.Sprintf(`PRAGMA user_version = %d;`, tt.start)); err != nil {
+				t.Fatal(err)
+			}
+
+			applied, err := MigrateDB(db)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MigrateDB error = %v, want error %v", err, tt.wantErr)
+			}
+			if applied != tt.wantApplied {
+				t.Errorf("applied = %d, want %d", applied, tt.wantApplied)
+			}
+			version, err := SchemaVersion(db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("version = %d, want %d", version, tt.wantVersion)
+			}
+			for _, table := range tt.wantTables {
+				if !tableExists(t, db, table) {
+					t.Errorf("table %s is missing", table)
+				}
+			}
+			for _, table := range tt.missing {
+				if tableExists(t, db, table) {
+					t.Errorf("table %s exists", table)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrateDBBacksUpDestructive(t *testing.T) {
+	useTempConfig(t)
+	saved := Migrations
+	Migrations = []Migration{
+		{Description: "create", Up: execSQL(`CREATE TABLE a (id INTEGER); INSERT INTO a VALUES (1);`)},
+		{Description: "drop", Destructive: true, Up: execSQL(`DROP TABLE a;`)},
+	}
+	t.Cleanup(func() { Migrations = saved })
+
+	db := openTestDB(t, filepath.Join(t.TempDir(), "test.db"))
+	if _, err := MigrateDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(DBBackupPath(0)); err == nil {
+		t.Error("backed up before a migration that isn't destructive")
+	}
+	backup := openTestDB(t, DBBackupPath(1))
+	var id int
+	if err := backup.QueryRow(`SELECT id FROM a;`).Scan(&id); err != nil || id != 1 {
+		t.Errorf("backup doesn't hold the dropped table: %v", err)
+	}
+}
+
+func TestMigrationsUpgradeLegacyDatabase(t *testing.T) {
+	useTempConfig(t)
+
+	// The schema created before the database was versioned.
+	db := openTestDB(t, filepath.Join(t.TempDir(), "test.db"))
+	_, err := db.Exec(`CREATE TABLE problems (id INTEGER PRIMARY KEY, name TEXT, timelimit FLOAT,
+memorylimit INTEGER, sourcesize INTEGER, credits TEXT, statement TEXT);
+INSERT INTO problems (id, name, statement) VALUES (1, 'sum', 'c3Vt');`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := MigrateDB(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(Migrations) {
+		t.Errorf("applied = %d, want %d", applied, len(Migrations))
+	}
+
+	var name, statement string
+	if err := db.QueryRow(`SELECT name, statement FROM problems WHERE id = 1;`).Scan(&name, &statement); err != nil {
+		t.Fatal(err)
+	}
+	if name != "sum" || statement != "c3Vt" {
+		t.Errorf("problem 1 = %q, %q after the upgrade", name, statement)
+	}
+
+	if applied, err := MigrateDB(db); err != nil || applied != 0 {
+		t.Errorf("second MigrateDB = %d, %v, want nothing to do", applied, err)
+	}
+}
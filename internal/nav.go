@@ -0,0 +1,126 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Screens are kept on a stack: Enter on a table row pushes the related screen
+// (a problem's statement, a user's profile, ...), Esc goes back to the previous
+// one and q quits.
+
+// RowOpener returns the screen opened when Enter is pressed on a row.
+type RowOpener func(row table.Row) (tea.Model, error)
+
+// OpenText is a RowOpener showing the text returned for the row.
+func OpenText(text func(row table.Row) (string, error)) RowOpener {
+	return func(row table.Row) (tea.Model, error) {
+		content, err := text(row)
+		if err != nil {
+			return nil, err
+		}
+		return NewTextModel(content), nil
+	}
+}
+
+type openMsg struct {
+	screen tea.Model
+	err    error
+}
+
+type backMsg struct{}
+
+// Back closes the current screen, quitting on the first one.
+func Back() tea.Msg {
+	return backMsg{}
+}
+
+type Navigator struct {
+	stack []tea.Model
+	size  *tea.WindowSizeMsg
+}
+
+func NewNavigator(screen tea.Model) *Navigator {
+	return &Navigator{stack: []tea.Model{screen}}
+}
+
+func (n *Navigator) top() tea.Model {
+	return n.stack[len(n.stack)-1]
+}
+
+func (n *Navigator) Init() tea.Cmd {
+	return n.top().Init()
+}
+
+func (n *Navigator) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return n, tea.Interrupt
+		}
+		// Keys only go to the screen on top.
+		var cmd tea.Cmd
+		n.stack[len(n.stack)-1], cmd = n.top().Update(msg)
+		return n, cmd
+
+	case openMsg:
+		// The screen that asked for it stops waiting or shows the error.
+		var cmd tea.Cmd
+		n.stack[len(n.stack)-1], cmd = n.top().Update(msg)
+		if msg.err != nil {
+			return n, cmd
+		}
+		return n, tea.Batch(cmd, n.push(msg.screen))
+
+	case backMsg:
+		n.stack = n.stack[:len(n.stack)-1]
+		if len(n.stack) == 0 {
+			return n, tea.Quit
+		}
+		return n, nil
+	}
+
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		n.size = &msg
+	}
+
+	// Everything else, e.g. a page loaded after its table was covered or a
+	// resize, reaches every screen.
+	var cmds []tea.Cmd
+	for i, screen := range n.stack {
+		var cmd tea.Cmd
+		n.stack[i], cmd = screen.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return n, tea.Batch(cmds...)
+}
+
+func (n *Navigator) push(screen tea.Model) tea.Cmd {
+	n.stack = append(n.stack, screen)
+	cmd := screen.Init()
+	if n.size != nil {
+		var sizeCmd tea.Cmd
+		n.stack[len(n.stack)-1], sizeCmd = screen.Update(*n.size)
+		cmd = tea.Batch(cmd, sizeCmd)
+	}
+	return cmd
+}
+
+func (n *Navigator) View() string {
+	return n.top().View()
+}
+
+// RunScreen shows screen, and the screens opened from it, until q is pressed
+// or the first screen is closed.
+func RunScreen(screen tea.Model, options ...tea.ProgramOption) {
+	if _, err := tea.NewProgram(NewNavigator(screen), options...).Run(); err != nil {
+		LogError(fmt.Errorf("error running program: %w", err))
+	}
+}
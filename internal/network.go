@@ -6,117 +6,230 @@
 package internal
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
+	"kncli/api"
 	"net/http"
-	"strings"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 )
 
-func CreateRequest(req http.Request, reqType RequestType, contentType ...string) *http.Request {
-
-	req.Header.Set("User-Agent", UserAgent)
-	token, hasToken := ReadToken()
-
-	switch reqType {
-	case RequestFormAuth, RequestFormGuest:
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	case RequestJSON:
-		req.Header.Set("Content-Type", "application/json")
-	case RequestDownloadZip, RequestInfo:
-		req.Header.Set("Content-Type", "application/zip")
-		req.Header.Set("Accept", "application/zip")
-		if reqType == RequestDownloadZip {
-			fmt.Println("Trying to obtain archive...")
-		}
-		cookie := &http.Cookie{
-			Name:  "kn-sessionid",
-			Value: token,
-		}
-		req.AddCookie(cookie)
-	case RequestMultipartForm:
-		if len(contentType) > 0 {
-			req.Header.Set("Content-Type", contentType[0])
-		} else {
-			LogError(fmt.Errorf("missing content type for multipart form request"))
-		}
-	default:
+var rootContext = context.Background()
+
+var apiClient *api.Client
+
+// Context is the root context of requests made on behalf of CLI commands. It
+// is cancelled on SIGINT and SIGTERM.
+func Context() context.Context {
+	return rootContext
+}
+
+func SetContext(ctx context.Context) {
+	rootContext = ctx
+}
+
+// Client returns the API client shared by every command.
+func Client() *api.Client {
+	if apiClient == nil {
+		apiClient = api.NewClient(api.DEFAULT_BASE_URL, api.TokenFunc(ReadToken))
+		apiClient.BeforeAuth = checkSession
+		apiClient.UserAgent = UserAgent
+		apiClient.Retry = api.DefaultRetryPolicy
+		apiClient.Limiter = api.NewRateLimiter(DEFAULT_RATE_LIMIT, DEFAULT_RATE_BURST)
+		apiClient.Cache = ResponseCache()
+		apiClient.CacheTTL = api.DefaultCacheTTL
 	}
+	return apiClient
+}
 
-	if hasToken {
-		req.Header.Set("Authorization", token)
-	} else if reqType == RequestFormAuth || reqType == RequestDownloadZip {
-		LogError(fmt.Errorf("you must be authenticated to do this"))
+// SetBaseURL points every endpoint at another Kilonova instance. Both the site
+// root and its /api/ path are accepted.
+func SetBaseURL(raw string) error {
+	baseURL, err := api.NormalizeBaseURL(raw)
+	if err != nil {
+		return err
 	}
+	Client().BaseURL = baseURL
+	return nil
+}
 
-	return &req
+// ResponseCache is the on-disk cache of API responses in the config dir.
+func ResponseCache() *api.DiskCache {
+	return &api.DiskCache{Dir: filepath.Join(GetConfigDir(), CACHEFOLDER)}
 }
 
-func MakeRequest(method, url string, ResponseBody io.Reader, reqType RequestType, contentType ...string) ([]byte, error) {
-	req, err := http.NewRequest(method, url, ResponseBody)
-	if err != nil {
-		LogError(fmt.Errorf("error creating request: %w", err))
-		return nil, err
+// NetworkFlags holds the global network flags; empty values were not set on
+// the command line.
+type NetworkFlags struct {
+	APIURL    string
+	Retries   string
+	RateLimit string
+	NoCache   string
+	Refresh   string
+	Trace     string
+
+	Timeout        string
+	ConnectTimeout string
+	Proxy          string
+	CAFile         string
+}
+
+// ConfigureClient applies the network settings, resolving each one from its
+// flag, environment variable or config key.
+func ConfigureClient(flags NetworkFlags) error {
+	if err := configureHTTPClient(flags); err != nil {
+		return err
 	}
-	req = CreateRequest(*req, reqType, contentType...)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		LogError(fmt.Errorf("error making request: %w", err))
-		return nil, err
+	if value, ok := Setting(flags.APIURL, ENV_API_URL, CONFIG_API_URL); ok {
+		if err := SetBaseURL(value); err != nil {
+			return err
+		}
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		LogError(fmt.Errorf("error reading response ResponseBody: %w", err))
-		return nil, err
+	if value, ok := Setting(flags.Retries, ENV_RETRIES, CONFIG_RETRIES); ok {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return fmt.Errorf("invalid number of retries %q", value)
+		}
+		Client().Retry.MaxRetries = retries
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		if reqType == RequestDatabase {
-			if strings.Contains(string(data), "not") {
-				return []byte("notfound"), nil
-			}
+	if value, ok := Setting(flags.RateLimit, ENV_RATE_LIMIT, CONFIG_RATE_LIMIT); ok {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid rate limit %q", value)
 		}
-		var res RawKilonovaResponse
-		if err := json.Unmarshal(data, &res); err != nil {
-			LogError(err)
+		if rate <= 0 {
+			Client().Limiter = nil
+		} else {
+			Client().Limiter = api.NewRateLimiter(rate, DEFAULT_RATE_BURST)
 		}
-		LogError(fmt.Errorf("error: %s", string(res.Data)))
 	}
 
-	return data, nil
-}
+	if value, ok := Setting(flags.NoCache, ENV_NO_CACHE, CONFIG_NO_CACHE); ok {
+		noCache, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid no-cache value %q", value)
+		}
+		if noCache {
+			Client().Cache = nil
+		}
+	}
+	Client().RefreshCache = flags.Refresh == BOOLTRUE
+
+	ttls := []struct {
+		key string
+		ttl *time.Duration
+	}{
+		{CONFIG_TTL_PROBLEM, &Client().CacheTTL.Problem},
+		{CONFIG_TTL_LANGUAGES, &Client().CacheTTL.Languages},
+		{CONFIG_TTL_STATEMENT, &Client().CacheTTL.Statement},
+	}
+	for _, t := range ttls {
+		value, ok := ConfigValue(t.key)
+		if !ok {
+			continue
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl < 0 {
+			return fmt.Errorf("invalid duration %q for %s", value, t.key)
+		}
+		*t.ttl = ttl
+	}
+
+	if err := configureFixtures(os.Getenv(ENV_RECORD), os.Getenv(ENV_REPLAY)); err != nil {
+		return err
+	}
+
+	if value, ok := Setting(flags.Trace, ENV_TRACE, ""); ok {
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 0 {
+			return fmt.Errorf("invalid trace level %q", value)
+		}
+		configureTrace(level)
+	}
 
-func MakeGetRequest(url string, ResponseBody io.Reader, reqType RequestType, contentType ...string) ([]byte, error) {
-	return MakeRequest("GET", url, ResponseBody, reqType, contentType...)
+	return nil
 }
 
-func MakePostRequest(url string, ResponseBody io.Reader, reqType RequestType, contentType ...string) ([]byte, error) {
-	return MakeRequest("POST", url, ResponseBody, reqType, contentType...)
+// configureTrace logs requests to stderr. It wraps the other transports so
+// that every retry and replayed request shows up.
+func configureTrace(level int) {
+	if level <= api.TraceOff {
+		return
+	}
+	wrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return &api.TraceTransport{Next: next, Out: os.Stderr, Level: level}
+	})
 }
 
-func PostJSON[T any](url string, payload any) (T, error) {
-	var result T
+// configureFixtures routes the client through a recording or replaying
+// transport. The cache is bypassed so that every request reaches it.
+func configureFixtures(recordDir, replayDir string) error {
+	if recordDir == "" && replayDir == "" {
+		return nil
+	}
+	if recordDir != "" && replayDir != "" {
+		return fmt.Errorf("%s and %s can't be used together", ENV_RECORD, ENV_REPLAY)
+	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		LogError(fmt.Errorf("failed to marshal JSON: %w", err))
-		return result, err
+	if recordDir != "" {
+		wrapTransport(func(next http.RoundTripper) http.RoundTripper {
+			return &api.RecordTransport{Dir: recordDir, Next: next}
+		})
+	} else {
+		wrapTransport(func(http.RoundTripper) http.RoundTripper {
+			return &api.ReplayTransport{Dir: replayDir}
+		})
+		Client().Limiter = nil
+		Client().Retry.MaxRetries = 0
 	}
 
-	ResponseBody, err := MakePostRequest(url, bytes.NewBuffer(jsonData), RequestJSON)
-	if err != nil {
-		return result, err
+	Client().Cache = nil
+	return nil
+}
+
+// wrapTransport replaces the transport of the shared HTTP client, keeping its other settings.
+func wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	httpClient := *Client().HTTPClient
+	httpClient.Transport = wrap(httpClient.Transport)
+	Client().HTTPClient = &httpClient
+}
+
+// configureHTTPClient builds the shared HTTP client from the timeout, proxy
+// and CA bundle settings.
+func configureHTTPClient(flags NetworkFlags) error {
+	opts := api.DefaultHTTPOptions
+
+	timeouts := []struct {
+		flag, env, key string
+		timeout        *time.Duration
+	}{
+		{flags.Timeout, ENV_TIMEOUT, CONFIG_TIMEOUT, &opts.Timeout},
+		{flags.ConnectTimeout, ENV_CONNECT_TIMEOUT, CONFIG_CONNECT_TIMEOUT, &opts.ConnectTimeout},
+	}
+	for _, t := range timeouts {
+		value, ok := Setting(t.flag, t.env, t.key)
+		if !ok {
+			continue
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return fmt.Errorf("invalid timeout %q", value)
+		}
+		*t.timeout = timeout
 	}
 
-	err = json.Unmarshal(ResponseBody, &result)
+	opts.Proxy, _ = Setting(flags.Proxy, "", CONFIG_PROXY)
+	opts.CAFile, _ = Setting(flags.CAFile, ENV_CA_FILE, CONFIG_CA_FILE)
+
+	httpClient, err := api.NewHTTPClient(opts)
 	if err != nil {
-		LogError(fmt.Errorf("failed to decode response: %w", err))
-		return result, err
+		return err
 	}
-
-	return result, nil
+	Client().HTTPClient = httpClient
+	return nil
 }
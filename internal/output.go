@@ -0,0 +1,290 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Listing commands print their results as an interactive table by default.
+// The other formats print the API structs themselves to stdout, so the output
+// can be piped into jq, spreadsheets or scripts, and never start the TUI.
+
+var OutputFormat = OUTPUT_TABLE
+
+var outputFormats = []string{OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_CSV, OUTPUT_TSV, OUTPUT_YAML}
+
+// SetOutputFormat selects the output format from the --output flag,
+// KNCLI_OUTPUT or the config.
+func SetOutputFormat(flagValue string) error {
+	value, ok := Setting(flagValue, ENV_OUTPUT, CONFIG_OUTPUT)
+	if !ok {
+		value = OUTPUT_TABLE
+	}
+	value = strings.ToLower(value)
+
+	for _, format := range outputFormats {
+		if value == format {
+			OutputFormat = value
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %q: must be one of %s", value, strings.Join(outputFormats, ", "))
+}
+
+// TabularOutput reports whether results are printed one record at a time, as
+// CSV/TSV lines or with an output template.
+func TabularOutput() bool {
+	return outputTemplate != nil || OutputFormat == OUTPUT_CSV || OutputFormat == OUTPUT_TSV
+}
+
+// StructuredOutput reports whether results are printed in a format or with a
+// template instead of the default output.
+func StructuredOutput() bool {
+	return outputTemplate != nil || OutputFormat != OUTPUT_TABLE
+}
+
+// PrintOutput prints data with the output template or in the selected format
+// and returns true, or returns false when the caller should print it its own
+// way. For CSV and TSV, data should be a slice: each element becomes a line,
+// with nested structs and maps flattened into "parent.child" columns.
+func PrintOutput(data any) bool {
+	var err error
+	switch {
+	case outputTemplate != nil:
+		err = writeTemplate(os.Stdout, data)
+	case OutputFormat == OUTPUT_TABLE:
+		return false
+	default:
+		err = WriteOutput(os.Stdout, OutputFormat, data)
+	}
+	if err != nil {
+		LogError(fmt.Errorf("failed to print output: %w", err))
+	}
+	return true
+}
+
+func WriteOutput(w io.Writer, format string, data any) error {
+	// A nil slice is an empty result, printed as [] rather than null.
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		data = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case OUTPUT_JSON:
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, encoded, "", "  "); err != nil {
+			return err
+		}
+		indented.WriteByte('\n')
+		_, err = indented.WriteTo(w)
+		return err
+	case OUTPUT_YAML:
+		value, err := orderedJSON(encoded)
+		if err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	case OUTPUT_CSV, OUTPUT_TSV:
+		value, err := orderedJSON(encoded)
+		if err != nil {
+			return err
+		}
+		records, ok := value.([]any)
+		if !ok {
+			records = []any{value}
+		}
+		columns, lines := flattenRecords(records)
+		if len(records) == 0 {
+			// An empty result only gets the header, taken from a zero element.
+			zero, err := zeroElement(data)
+			if err != nil || zero == nil {
+				return err
+			}
+			columns, _ = flattenRecords([]any{zero})
+		}
+		return writeRecords(w, format, columns, lines)
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// orderedJSON decodes JSON keeping the order of object keys, which is the
+// order of the struct fields.
+func orderedJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeOrdered(decoder)
+}
+
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := yaml.MapSlice{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	case nil:
+		return nil, nil
+	}
+
+	if number, ok := token.(json.Number); ok {
+		if integer, err := number.Int64(); err == nil {
+			return integer, nil
+		}
+		return number.Float64()
+	}
+	return token, nil
+}
+
+// zeroElement returns a zero element of the slice data, decoded like the
+// records, or nil when its elements aren't objects.
+func zeroElement(data any) (any, error) {
+	elem := reflect.TypeOf(data).Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(reflect.New(elem).Interface())
+	if err != nil {
+		return nil, err
+	}
+	return orderedJSON(encoded)
+}
+
+// flattenRecords flattens each record into a line and returns the columns
+// in the order they first appear.
+func flattenRecords(records []any) ([]string, []map[string]string) {
+	var columns []string
+	seen := map[string]bool{}
+	var lines []map[string]string
+	for _, record := range records {
+		line := map[string]string{}
+		flatten("", record, line, func(column string) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		})
+		lines = append(lines, line)
+	}
+	return columns, lines
+}
+
+func writeRecords(w io.Writer, format string, columns []string, lines []map[string]string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if format == OUTPUT_TSV {
+		writer.Comma = '\t'
+	}
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		fields := make([]string, len(columns))
+		for i, column := range columns {
+			fields[i] = line[column]
+		}
+		if err := writer.Write(fields); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// flatten stores the scalar fields of value in line. Objects become
+// "parent.child" columns; arrays are kept as JSON in a single column.
+func flatten(prefix string, value any, line map[string]string, column func(string)) {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		for _, item := range value {
+			name := fmt.Sprint(item.Key)
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			flatten(name, item.Value, line, column)
+		}
+		return
+	case []any:
+		encoded, _ := json.Marshal(plain(value))
+		line[prefix] = string(encoded)
+	case nil:
+		line[prefix] = ""
+	default:
+		line[prefix] = fmt.Sprint(value)
+	}
+	column(prefix)
+}
+
+// plain converts ordered objects back into values encoding/json can marshal.
+func plain(value any) any {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		object := make(map[string]any, len(value))
+		for _, item := range value {
+			object[fmt.Sprint(item.Key)] = plain(item.Value)
+		}
+		return object
+	case []any:
+		array := make([]any, len(value))
+		for i, item := range value {
+			array[i] = plain(item)
+		}
+		return array
+	}
+	return value
+}
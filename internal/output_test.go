@@ -0,0 +1,216 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testLimits struct {
+	Time   float64 `json:"time"`
+	Memory int     `json:"memory"`
+}
+
+type testProblem struct {
+	ID     int        `json:"id"`
+	Name   string     `json:"name"`
+	Limits testLimits `json:"limits"`
+	Tags   []string   `json:"tags"`
+	Source *string    `json:"source"`
+}
+
+var testProblems = []testProblem{
+	{ID: 1, Name: "sum", Limits: testLimits{0.5, 64}, Tags: []string{"math", "easy"}},
+	{ID: 2, Name: "a, b", Limits: testLimits{1, 128}},
+}
+
+func TestWriteOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   any
+		want   string
+	}{
+		{
+			name:   "json",
+			format: OUTPUT_JSON,
+			data:   testProblems[:1],
+			want: `[
+  {
+    "id": 1,
+    "name": "sum",
+    "limits": {
+      "time": 0.5,
+      "memory": 64
+    },
+    "tags": [
+      "math",
+      "easy"
+    ],
+    "source": null
+  }
+]
+`,
+		},
+		{
+			name:   "yaml keeps the field order",
+			format: OUTPUT_YAML,
+			data:   testProblems[:1],
+			want: `- id: 1
+  name: sum
+  limits:
+    time: 0.5
+    memory: 64
+  tags:
+  - math
+  - easy
+  source: null
+`,
+		},
+		{
+			name:   "csv flattens nested fields",
+			format: OUTPUT_CSV,
+			data:   testProblems,
+			want: `id,name,limits.time,limits.memory,tags,source
+1,sum,0.5,64,"[""math"",""easy""]",
+2,"a, b",1,128,,
+`,
+		},
+		{
+			name:   "tsv",
+			format: OUTPUT_TSV,
+			data:   testProblems[1:],
+			want:   "id\tname\tlimits.time\tlimits.memory\ttags\tsource\n2\ta, b\t1\t128\t\t\n",
+		},
+		{
+			name:   "csv of a single struct",
+			format: OUTPUT_CSV,
+			data:   testLimits{2, 256},
+			want:   "time,memory\n2,256\n",
+		},
+		{
+			name:   "empty json",
+			format: OUTPUT_JSON,
+			data:   []testProblem(nil),
+			want:   "[]\n",
+		},
+		{
+			name:   "empty yaml",
+			format: OUTPUT_YAML,
+			data:   []testProblem(nil),
+			want:   "[]\n",
+		},
+		{
+			name:   "empty csv prints the header",
+			format: OUTPUT_CSV,
+			data:   []*testProblem{},
+			want:   "id,name,limits.time,limits.memory,tags,source\n",
+		},
+		{
+			name:   "empty csv of scalars",
+			format: OUTPUT_CSV,
+			data:   []string{},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := WriteOutput(&out, tt.format, tt.data); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("WriteOutput =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+
+	if err := WriteOutput(&bytes.Buffer{}, "xml", testProblems); err == nil {
+		t.Error("WriteOutput accepted an unknown format")
+	}
+}
+
+func TestFlattenRecords(t *testing.T) {
+	decode := func(data string) any {
+		value, err := orderedJSON([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	tests := []struct {
+		name        string
+		records     []any
+		wantColumns []string
+		wantLines   []map[string]string
+	}{
+		{
+			name:        "nested objects",
+			records:     []any{decode(`{"a": 1, "b": {"c": "x", "d": {"e": true}}}`)},
+			wantColumns: []string{"a", "b.c", "b.d.e"},
+			wantLines:   []map[string]string{{"a": "1", "b.c": "x", "b.d.e": "true"}},
+		},
+		{
+			name:        "arrays stay JSON",
+			records:     []any{decode(`{"a": [1, {"b": 2}], "c": null}`)},
+			wantColumns: []string{"a", "c"},
+			wantLines:   []map[string]string{{"a": `[1,{"b":2}]`, "c": ""}},
+		},
+		{
+			name:        "columns in order of appearance",
+			records:     []any{decode(`{"a": 1}`), decode(`{"b": 2, "a": 3}`)},
+			wantColumns: []string{"a", "b"},
+			wantLines:   []map[string]string{{"a": "1"}, {"a": "3", "b": "2"}},
+		},
+		{
+			name:        "scalars",
+			records:     []any{decode(`"x"`), decode(`2.5`)},
+			wantColumns: []string{""},
+			wantLines:   []map[string]string{{"": "x"}, {"": "2.5"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, lines := flattenRecords(tt.records)
+			if !reflect.DeepEqual(columns, tt.wantColumns) {
+				t.Errorf("columns = %q, want %q", columns, tt.wantColumns)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("lines = %v, want %v", lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestWriteRecords(t *testing.T) {
+	lines := []map[string]string{{"a": "1", "b": "x\"y"}, {"b": "2"}}
+
+	tests := []struct {
+		name    string
+		format  string
+		columns []string
+		want    string
+	}{
+		{"csv", OUTPUT_CSV, []string{"a", "b"}, "a,b\n1,\"x\"\"y\"\n,2\n"},
+		{"tsv", OUTPUT_TSV, []string{"b", "a"}, "b\ta\n\"x\"\"y\"\t1\n2\t\n"},
+		{"no columns", OUTPUT_CSV, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := writeRecords(&out, tt.format, tt.columns, lines); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("writeRecords = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PagedTable is a table whose rows are fetched a page at a time: the next page
+// is loaded when the cursor reaches the last row.
+
+// PageLoader fetches the rows starting at offset, and the total number of rows.
+type PageLoader func(offset int) (rows []table.Row, total int, err error)
+
+type pageMsg struct {
+	table *PagedTable
+	rows  []table.Row
+	total int
+	err   error
+}
+
+type PagedTable struct {
+	tableView
+
+	load    PageLoader
+	offset  int
+	total   int
+	loading bool
+	err     error
+}
+
+// NewPagedTable returns a table that starts loading at offset.
+func NewPagedTable(columns []table.Column, offset int, load PageLoader, open RowOpener) *PagedTable {
+	return &PagedTable{
+		tableView: newTableView(CreateTable(columns, nil), open),
+		load:      load,
+		offset:    offset,
+		total:     -1,
+	}
+}
+
+func (m *PagedTable) Init() tea.Cmd {
+	return m.loadPage()
+}
+
+func (m *PagedTable) loadPage() tea.Cmd {
+	if m.loading || (m.total >= 0 && m.offset >= m.total) {
+		return nil
+	}
+	m.loading = true
+	m.updateNote()
+
+	offset := m.offset
+	return func() tea.Msg {
+		rows, total, err := m.load(offset)
+		return pageMsg{table: m, rows: rows, total: total, err: err}
+	}
+}
+
+func (m *PagedTable) updateNote() {
+	switch {
+	case m.err != nil:
+		m.note = fmt.Sprintf("error: %v", m.err)
+	case m.loading:
+		m.note = "loading…"
+	case m.total >= 0:
+		m.note = fmt.Sprintf("%d of %d loaded", len(m.rows), m.total)
+	}
+}
+
+func (m *PagedTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case pageMsg:
+		if msg.table != m {
+			return m, nil
+		}
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.total = msg.total
+			m.offset += len(msg.rows)
+			if len(msg.rows) == 0 {
+				// The list shrank since the count was returned.
+				m.total = m.offset
+			}
+			m.appendRows(msg.rows)
+		}
+		m.updateNote()
+		return m, nil
+
+	case tea.KeyMsg:
+		if !m.capturing() {
+			switch key := msg.String(); {
+			case key == "q":
+				return m, tea.Quit
+			case key == "esc" && m.filter.Value() == "":
+				return m, Back
+			}
+		}
+	}
+
+	cmd := m.update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok && !m.capturing() && m.total >= 0 && m.table.Cursor() >= len(m.table.Rows())-1 {
+		return m, tea.Batch(cmd, m.loadPage())
+	}
+	return m, cmd
+}
+
+func (m *PagedTable) View() string {
+	if m.total < 0 && m.err == nil {
+		return "\n  Loading…"
+	}
+	return m.view()
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/bubbles/table"
+	"golang.org/x/term"
+)
+
+// Tables and statements open in a full-screen viewer only when stdout is a
+// terminal. Under a pipe or with --no-tui they are printed once instead, so
+// scripts never wait for 'q'.
+
+const DEFAULT_TERMINAL_WIDTH = 80
+
+var noTUI = false
+
+// SetNoTUI disables the interactive viewers, from --no-tui, KNCLI_NO_TUI or the config.
+func SetNoTUI(flagValue string) error {
+	value, ok := Setting(flagValue, ENV_NO_TUI, CONFIG_NO_TUI)
+	if !ok {
+		return nil
+	}
+	disabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid no-tui value %q", value)
+	}
+	noTUI = disabled
+	return nil
+}
+
+// UseTUI reports whether results can be shown in an interactive viewer.
+func UseTUI() bool {
+	return !noTUI && IsTerminal(os.Stdout)
+}
+
+// TerminalWidth returns the width of the terminal attached to stdout or
+// stderr, then $COLUMNS, then DEFAULT_TERMINAL_WIDTH.
+func TerminalWidth() int {
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return DEFAULT_TERMINAL_WIDTH
+}
+
+// PrintTable writes rows as a text table with aligned columns.
+func PrintTable(w io.Writer, columns []table.Column, rows []table.Row) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	titles := make([]string, len(columns))
+	rules := make([]string, len(columns))
+	for i, column := range columns {
+		titles[i] = column.Title
+		rules[i] = strings.Repeat("-", len([]rune(column.Title)))
+	}
+	fmt.Fprintln(writer, strings.Join(titles, "\t"))
+	fmt.Fprintln(writer, strings.Join(rules, "\t"))
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			// Tabs and newlines would break the alignment.
+			cells[i] = strings.Join(strings.Fields(cell), " ")
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	return writer.Flush()
+}
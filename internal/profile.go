@@ -0,0 +1,131 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Each profile has its own token and config.yaml. The default profile uses
+// the files directly in the config dir, so existing installs keep working;
+// other profiles live in profiles/<name>.
+
+var profileName = ""
+
+var validProfileName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func ValidateProfileName(name string) error {
+	if !validProfileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// SetProfile selects the profile used by this run, from the --profile flag,
+// KNCLI_PROFILE or the profile chosen with 'profile use'.
+func SetProfile(flagValue string) error {
+	name := flagValue
+	if name == "" {
+		name = os.Getenv(ENV_PROFILE)
+	}
+	if name == "" {
+		name = SavedProfile()
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	profileName = name
+	return nil
+}
+
+// Profile returns the name of the active profile.
+func Profile() string {
+	if profileName == "" {
+		return SavedProfile()
+	}
+	return profileName
+}
+
+// SavedProfile returns the profile chosen with 'profile use'.
+func SavedProfile() string {
+	data, err := os.ReadFile(filepath.Join(GetConfigDir(), PROFILEFILENAME))
+	if err != nil {
+		return DEFAULT_PROFILE
+	}
+	name := strings.TrimSpace(string(data))
+	if ValidateProfileName(name) != nil {
+		return DEFAULT_PROFILE
+	}
+	return name
+}
+
+func SaveProfile(name string) error {
+	return WriteFileAtomic(filepath.Join(GetConfigDir(), PROFILEFILENAME), []byte(name), 0644)
+}
+
+// ProfileDir returns the directory holding the token and config of a profile.
+func ProfileDir(name string) string {
+	if name == DEFAULT_PROFILE {
+		return GetConfigDir()
+	}
+	return filepath.Join(GetConfigDir(), PROFILESFOLDER, name)
+}
+
+func ProfileExists(name string) bool {
+	if name == DEFAULT_PROFILE {
+		return true
+	}
+	info, err := os.Stat(ProfileDir(name))
+	return err == nil && info.IsDir()
+}
+
+func CreateProfile(name string) error {
+	return os.MkdirAll(ProfileDir(name), 0700)
+}
+
+func RemoveProfile(name string) error {
+	if name == DEFAULT_PROFILE {
+		return fmt.Errorf("the default profile can't be removed")
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile %q doesn't exist", name)
+	}
+	if err := os.RemoveAll(ProfileDir(name)); err != nil {
+		return err
+	}
+	if SavedProfile() == name {
+		return SaveProfile(DEFAULT_PROFILE)
+	}
+	return nil
+}
+
+// Profiles lists the default profile followed by the others in alphabetical order.
+func Profiles() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(GetConfigDir(), PROFILESFOLDER))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateProfileName(entry.Name()) == nil && entry.Name() != DEFAULT_PROFILE {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DEFAULT_PROFILE}, names...), nil
+}
+
+// HasToken reports whether a profile is signed in.
+func HasToken(name string) bool {
+	_, err := os.Stat(filepath.Join(ProfileDir(name), TOKENFILENAME))
+	return err == nil
+}
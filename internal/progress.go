@@ -0,0 +1,65 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const progressWidth = 30
+
+// Progress draws a single-line progress bar with a count and ETA on stderr.
+type Progress struct {
+	Title string
+	Total int
+
+	mu    sync.Mutex
+	done  int
+	start time.Time
+	out   io.Writer
+}
+
+func NewProgress(title string, total int) *Progress {
+	p := &Progress{Title: title, Total: total, start: time.Now(), out: os.Stderr}
+	p.draw()
+	return p
+}
+
+func (p *Progress) Increment() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.draw()
+}
+
+// Finish ends the progress line.
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.out)
+}
+
+func (p *Progress) draw() {
+	filled := progressWidth
+	if p.Total > 0 {
+		filled = p.done * progressWidth / p.Total
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled)
+
+	eta := "--"
+	if p.done > 0 {
+		elapsed := time.Since(p.start)
+		remaining := time.Duration(float64(elapsed) / float64(p.done) * float64(p.Total-p.done))
+		eta = remaining.Round(time.Second).String()
+	}
+
+	fmt.Fprintf(p.out, "\r%s [%s] %d/%d ETA %s   ", p.Title, bar, p.done, p.Total, eta)
+}
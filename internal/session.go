@@ -0,0 +1,118 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// The expiry of the session is kept in session.kn, next to the token, so the
+// CLI can warn before it runs out or extend it automatically.
+
+var sessionChecked atomic.Bool
+
+func sessionPath() string {
+	return filepath.Join(ProfileDir(Profile()), SESSIONFILENAME)
+}
+
+// SessionExpiry returns when the session of the active profile expires, if known.
+func SessionExpiry() (time.Time, bool) {
+	data, err := os.ReadFile(sessionPath())
+	if err != nil {
+		return time.Time{}, false
+	}
+	expiry, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiry, true
+}
+
+func WriteSessionExpiry(expiry time.Time) error {
+	return WriteFileAtomic(sessionPath(), []byte(expiry.Format(time.RFC3339)), 0600)
+}
+
+func removeSessionExpiry() {
+	_ = os.Remove(sessionPath())
+}
+
+// RefreshSessionExpiry extends the session and records the new expiry. It is
+// also how the expiry of a new session is learned, since login doesn't return it.
+func RefreshSessionExpiry() (time.Time, error) {
+	expiry, err := Client().ExtendSession(Context())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := WriteSessionExpiry(expiry); err != nil {
+		return time.Time{}, err
+	}
+	return expiry, nil
+}
+
+// SessionAutoExtend returns whether sessions are extended automatically and
+// how close to the expiry that happens.
+func SessionAutoExtend() (bool, time.Duration) {
+	window := DEFAULT_SESSION_WINDOW
+	if value, ok := ConfigValue(CONFIG_SESSION_WINDOW); ok {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			window = parsed
+		}
+	}
+
+	value, ok := Setting("", ENV_SESSION_AUTO_EXTEND, CONFIG_SESSION_AUTO_EXTEND)
+	if !ok {
+		return false, window
+	}
+	enabled, _ := strconv.ParseBool(value)
+	return enabled, window
+}
+
+// checkSession runs once, before the first request of a command that needs
+// the session: it extends a session about to expire when auto-extension is
+// on, and otherwise warns about it.
+func checkSession() {
+	if !sessionChecked.CompareAndSwap(false, true) {
+		return
+	}
+
+	expiry, ok := SessionExpiry()
+	if !ok {
+		return
+	}
+	remaining := time.Until(expiry)
+	autoExtend, window := SessionAutoExtend()
+
+	switch {
+	case remaining <= 0:
+		fmt.Fprintf(os.Stderr, "Warning: your session expired on %s. Sign in again.\n", expiry.Local().Format("2006-01-02 15:04"))
+	case autoExtend && remaining <= window:
+		if _, err := RefreshSessionExpiry(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to extend your session: %v\n", err)
+		}
+	case remaining <= SESSION_WARNING:
+		fmt.Fprintf(os.Stderr, "Warning: your session expires in %s. Run 'settings extendsession' or set %s: true in the config.\n",
+			FormatDuration(remaining), CONFIG_SESSION_AUTO_EXTEND)
+	}
+}
+
+// FormatDuration prints a duration in days and hours, e.g. "2d 5h".
+func FormatDuration(d time.Duration) string {
+	if d < time.Hour {
+		return d.Round(time.Minute).String()
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	if days == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", days, hours)
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tableView is the table shared by the table models. It keeps every row and
+// shows those matching the filter typed after '/', sorted by the column whose
+// number was pressed (again to reverse, 0 for the original order). Enter opens
+// the selected row when the table has an opener.
+
+const tableChrome = 5 // margins, status line and help line
+
+var statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+type tableView struct {
+	table   table.Model
+	columns []table.Column
+	rows    []table.Row
+
+	filter     textinput.Model
+	filtering  bool
+	sortColumn int // 1-based, 0 keeps the original order
+	descending bool
+
+	// note is appended to the status line by the model owning the table.
+	note string
+
+	open    RowOpener
+	opening bool
+	openErr error
+
+	width  int
+	height int
+}
+
+func newTableView(t table.Model, open RowOpener) tableView {
+	filter := textinput.New()
+	filter.Prompt = "/"
+	filter.Placeholder = "filter"
+
+	return tableView{
+		table:   t,
+		columns: t.Columns(),
+		rows:    t.Rows(),
+		filter:  filter,
+		open:    open,
+	}
+}
+
+// capturing reports whether keys go to the filter instead of the table.
+func (v *tableView) capturing() bool {
+	return v.filtering
+}
+
+func (v *tableView) update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.width = msg.Width
+		v.height = msg.Height
+		v.table.SetHeight(max(msg.Height-tableChrome, 3))
+		return nil
+
+	case openMsg:
+		v.opening = false
+		v.openErr = msg.err
+		return nil
+
+	case tea.KeyMsg:
+		key := msg.String()
+		v.openErr = nil
+
+		if v.filtering {
+			switch key {
+			case "enter":
+				v.filtering = false
+				v.filter.Blur()
+				return nil
+			case "esc":
+				v.filtering = false
+				v.filter.Blur()
+				v.filter.SetValue("")
+				v.apply()
+				return nil
+			}
+			var cmd tea.Cmd
+			v.filter, cmd = v.filter.Update(msg)
+			v.apply()
+			return cmd
+		}
+
+		switch {
+		case key == "/":
+			v.filtering = true
+			return v.filter.Focus()
+		case key == "esc" && v.filter.Value() != "":
+			v.filter.SetValue("")
+			v.apply()
+			return nil
+		case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+			v.sortBy(int(key[0] - '0'))
+			return nil
+		case key == "enter" && v.open != nil:
+			return v.openSelected()
+		}
+	}
+
+	var cmd tea.Cmd
+	v.table, cmd = v.table.Update(msg)
+	return cmd
+}
+
+func (v *tableView) openSelected() tea.Cmd {
+	row := v.table.SelectedRow()
+	if row == nil || v.opening {
+		return nil
+	}
+	v.opening = true
+
+	open := v.open
+	return func() tea.Msg {
+		screen, err := open(row)
+		return openMsg{screen: screen, err: err}
+	}
+}
+
+func (v *tableView) sortBy(column int) {
+	if column > len(v.columns) {
+		return
+	}
+	if column == v.sortColumn && column != 0 {
+		v.descending = !v.descending
+	} else {
+		v.sortColumn = column
+		v.descending = false
+	}
+
+	columns := make([]table.Column, len(v.columns))
+	copy(columns, v.columns)
+	if column > 0 {
+		arrow := " ▲"
+		if v.descending {
+			arrow = " ▼"
+		}
+		columns[column-1].Title += arrow
+		columns[column-1].Width += lipgloss.Width(arrow)
+	}
+	v.table.SetColumns(columns)
+	v.apply()
+}
+
+// apply shows the rows matching the filter in the chosen order.
+func (v *tableView) apply() {
+	needle := strings.ToLower(v.filter.Value())
+
+	var rows []table.Row
+	for _, row := range v.rows {
+		if needle == "" || rowContains(row, needle) {
+			rows = append(rows, row)
+		}
+	}
+
+	if v.sortColumn > 0 {
+		column := v.sortColumn - 1
+		sort.SliceStable(rows, func(i, j int) bool {
+			if v.descending {
+				return lessCell(rows[j][column], rows[i][column])
+			}
+			return lessCell(rows[i][column], rows[j][column])
+		})
+	}
+
+	v.table.SetRows(rows)
+	v.table.SetCursor(0)
+}
+
+// appendRows adds rows, e.g. a page loaded later, keeping the cursor in place.
+func (v *tableView) appendRows(rows []table.Row) {
+	cursor := v.table.Cursor()
+	v.rows = append(v.rows, rows...)
+	v.apply()
+	v.table.SetCursor(cursor)
+}
+
+func rowContains(row table.Row, needle string) bool {
+	for _, cell := range row {
+		if strings.Contains(strings.ToLower(cell), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// lessCell compares numbers by value and everything else as text; dates are
+// printed as "2006-01-02 15:04:05", so they sort correctly as text.
+func lessCell(a, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+func (v *tableView) status() string {
+	if v.filtering {
+		return v.filter.View()
+	}
+
+	shown := len(v.table.Rows())
+	status := "no rows"
+	if shown > 0 {
+		status = fmt.Sprintf("row %d/%d", v.table.Cursor()+1, shown)
+	}
+	if shown != len(v.rows) {
+		status += fmt.Sprintf(" (%d total)", len(v.rows))
+	}
+	if filter := v.filter.Value(); filter != "" {
+		status += fmt.Sprintf(" · filter %q", filter)
+	}
+	if v.sortColumn > 0 {
+		order := "ascending"
+		if v.descending {
+			order = "descending"
+		}
+		status += fmt.Sprintf(" · sorted by %s, %s", v.columns[v.sortColumn-1].Title, order)
+	}
+	if v.note != "" {
+		status += " · " + v.note
+	}
+	switch {
+	case v.opening:
+		status += " · opening…"
+	case v.openErr != nil:
+		status += fmt.Sprintf(" · error: %v", v.openErr)
+	}
+	return status
+}
+
+func (v *tableView) view() string {
+	tableView := lipgloss.NewStyle().Margin(1, 2, 0).Render(v.table.View())
+
+	spaceLines := v.height - (strings.Count(tableView, "\n") + 1) - 3
+	spacing := strings.Repeat("\n", max(spaceLines, 0))
+
+	help := "↑/↓ move · pgup/pgdn page · home/end · / filter · 1-9 sort · "
+	if v.open != nil {
+		help += "enter open · "
+	}
+	help += "esc back · q quit"
+	return tableView + spacing + "\n\n" + statusStyle.Render(v.status()) + "\n" + help
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	tokenMu     sync.Mutex
+	tokenLoaded bool
+	tokenValue  string
+	tokenErr    error
+)
+
+func tokenPath() string {
+	return filepath.Join(ProfileDir(Profile()), TOKENFILENAME)
+}
+
+// Read Token Function
+
+// ReadToken returns the session token, or "" when signed out, decrypting
+// token.kn on first use. Tokens in the old format are re-encrypted on the way.
+// A token that can't be decrypted, e.g. after a wrong passphrase or without
+// key.kn, is reported as an error.
+func ReadToken() (string, error) {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
+	if tokenLoaded {
+		return tokenValue, tokenErr
+	}
+	tokenLoaded = true
+
+	data, err := os.ReadFile(tokenPath())
+	if err != nil {
+		return "", nil
+	}
+
+	token, legacy, err := Decrypt(string(bytes.TrimSpace(data)))
+	if err != nil {
+		tokenErr = fmt.Errorf("failed to decrypt the saved token: %w; sign in again with 'signin' or remove it with 'logout'", err)
+		return "", tokenErr
+	}
+
+	if legacy {
+		// The old format still works, so this isn't worth failing for.
+		if err := writeToken(token); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to migrate token: %v\n", err)
+		}
+	}
+
+	tokenValue = token
+	return token, nil
+}
+
+// SignedIn reports whether the active profile has a usable session token.
+func SignedIn() bool {
+	token, err := ReadToken()
+	return err == nil && token != ""
+}
+
+// WriteToken encrypts and stores a new session token, readable only by the current user.
+func WriteToken(token string) error {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
+	if err := writeToken(token); err != nil {
+		return err
+	}
+	tokenLoaded, tokenValue, tokenErr = true, token, nil
+	return nil
+}
+
+func writeToken(token string) error {
+	if err := CreateProfile(Profile()); err != nil {
+		return err
+	}
+
+	encrypted, err := Encrypt(token)
+	if err != nil {
+		return fmt.Errorf("error encrypting token: %w", err)
+	}
+	if err := WriteFileAtomic(tokenPath(), []byte(encrypted), 0600); err != nil {
+		return fmt.Errorf("error writing auth token to file: %w", err)
+	}
+	return nil
+}
+
+func RemoveToken() {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
+	_ = os.Remove(tokenPath())
+	removeSessionExpiry()
+	tokenLoaded, tokenValue, tokenErr = true, "", nil
+}
+
+// UnlockToken asks for the passphrase of a passphrase-protected token up
+// front, before a spinner or TUI takes over the terminal.
+func UnlockToken() {
+	data, err := os.ReadFile(tokenPath())
+	if err != nil || !strings.HasPrefix(string(data), tokenFormat+":"+schemeScrypt+":") {
+		return
+	}
+	if os.Getenv(ENV_PASSPHRASE) == "" && !IsTerminal(os.Stdin) {
+		return
+	}
+	ReadToken()
+}
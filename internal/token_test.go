@@ -0,0 +1,124 @@
+// Copyright (c) 2025 @drclcomputers. All rights reserved.
+//
+// This work is licensed under the terms of the MIT license.
+// For a copy, see <https://opensource.org/licenses/MIT>.
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// resetToken forgets the token read by an earlier test.
+func resetToken() {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+	tokenLoaded, tokenValue, tokenErr = false, "", nil
+}
+
+func TestReadToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     func(t *testing.T) string // contents of token.kn, "" for none
+		breakKey   bool
+		want       string
+		wantErr    bool
+		wantSigned bool
+	}{
+		{
+			name:   "signed out",
+			stored: func(t *testing.T) string { return "" },
+		},
+		{
+			name: "current format",
+			stored: func(t *testing.T) string {
+				encrypted, err := Encrypt("session-token")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return encrypted
+			},
+			want:       "session-token",
+			wantSigned: true,
+		},
+		{
+			name:       "legacy format",
+			stored:     func(t *testing.T) string { return legacyToken(t, "session-token") },
+			want:       "session-token",
+			wantSigned: true,
+		},
+		{
+			name: "key file deleted",
+			stored: func(t *testing.T) string {
+				encrypted, err := Encrypt("session-token")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return encrypted
+			},
+			breakKey: true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := useTempConfig(t)
+			resetToken()
+			t.Cleanup(resetToken)
+
+			if stored := tt.stored(t); stored != "" {
+				if err := os.WriteFile(tokenPath(), []byte(stored), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.breakKey {
+				if err := os.Remove(filepath.Join(dir, KEYFILENAME)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			token, err := ReadToken()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadToken error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "signin") {
+				t.Errorf("error %q doesn't say how to recover", err)
+			}
+			if token != tt.want {
+				t.Errorf("ReadToken = %q, want %q", token, tt.want)
+			}
+			if SignedIn() != tt.wantSigned {
+				t.Errorf("SignedIn = %v, want %v", !tt.wantSigned, tt.wantSigned)
+			}
+		})
+	}
+}
+
+func TestReadTokenMigratesLegacy(t *testing.T) {
+	useTempConfig(t)
+	resetToken()
+	t.Cleanup(resetToken)
+
+	if err := os.WriteFile(tokenPath(), []byte(legacyToken(t, "session-token")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadToken(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(tokenPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), tokenFormat+":") {
+		t.Fatalf("token.kn = %q, want it re-encrypted in the %s format", data, tokenFormat)
+	}
+
+	resetToken()
+	if token, err := ReadToken(); err != nil || token != "session-token" {
+		t.Errorf("ReadToken after the migration = %q, %v", token, err)
+	}
+}
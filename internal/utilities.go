@@ -6,27 +6,38 @@
 package internal
 
 import (
-	"bytes"
+	"bufio"
 	b64 "encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"kncli/api"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
 )
 
 // Utility Functions
 
 func ProblemExists(ID string) bool {
-	url := fmt.Sprintf(URL_PROBLEM, ID)
-	body, _ := MakeGetRequest(url, nil, RequestNone)
-	if string(body) == "notfound" {
+	_, err := Client().GetProblem(Context(), ID)
+
+	var notFound *api.NotFoundError
+	if errors.As(err, &notFound) {
 		return false
 	}
-	return true
+	if err != nil {
+		LogError(err)
+	}
+	return err == nil
 }
 
 func FileExists(filename string) bool {
@@ -75,42 +86,42 @@ func ParseTime(timeStr string) (string, error) {
 }
 
 func GetUserID() string {
-	ResponseBody, err := MakeGetRequest(URL_SELF, nil, RequestFormAuth)
+	user, err := Client().Self(Context())
 	if err != nil {
 		LogError(fmt.Errorf("failed to retrieve user info: %w", err))
 		return ""
 	}
 
-	var user UserId
-	if err := json.Unmarshal(ResponseBody, &user); err != nil {
-		LogError(fmt.Errorf("failed to parse user ID from response: %w", err))
-		return ""
-	}
-
-	return strconv.Itoa(user.Data.ID)
+	return strconv.Itoa(user.Id)
 }
 
 func GetAProblemName(problemID string) (string, error) {
-	url := fmt.Sprintf(URL_PROBLEM, problemID)
-	ResponseBody, err := MakeGetRequest(url, nil, RequestNone)
+	problem, err := Client().GetProblem(Context(), problemID)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch problem details: %w", err)
 	}
-
-	var info ProblemInfo
-	if err := json.Unmarshal(ResponseBody, &info); err != nil {
-		return "", fmt.Errorf("failed to parse problem info: %w", err)
-	}
-	return info.Data.Name, nil
+	return problem.Name, nil
 }
 
+// GetConfigDir returns $XDG_CONFIG_HOME/kncli, or ~/.config/kncli when the
+// variable is unset, creating it if needed.
 func GetConfigDir() string {
 	homedir, err := os.UserHomeDir()
 	if err != nil {
 		LogError(err)
 		return "error"
 	}
+
 	configDir := filepath.Join(homedir, CONFIGFOLDER, KNCLIFOLDER)
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(configHome) {
+		legacyDir := configDir
+		configDir = filepath.Join(configHome, KNCLIFOLDER)
+		legacyConfigOnce.Do(func() { keepLegacyConfig = !moveLegacyConfigDir(legacyDir, configDir) })
+		if keepLegacyConfig {
+			configDir = legacyDir
+		}
+	}
+
 	err = os.MkdirAll(configDir, 0755)
 	if err != nil {
 		LogError(err)
@@ -119,27 +130,94 @@ func GetConfigDir() string {
 	return configDir
 }
 
-// Read Token Function
-
-func ReadToken() (string, bool) {
-	tokenPath := filepath.Join(GetConfigDir(), TOKENFILENAME)
+var (
+	legacyConfigOnce sync.Once
+	keepLegacyConfig bool
+)
 
-	data, err := os.ReadFile(tokenPath)
-	if err != nil {
-		return "", false
+// moveLegacyConfigDir moves ~/.config/kncli, used even when XDG_CONFIG_HOME
+// was set by older versions, to configDir if only the former exists. It
+// returns false when the legacy directory couldn't be moved and stays in use.
+func moveLegacyConfigDir(legacyDir, configDir string) bool {
+	if legacyDir == configDir {
+		return true
+	}
+	if _, err := os.Stat(configDir); !os.IsNotExist(err) {
+		return true
+	}
+	if info, err := os.Stat(legacyDir); err != nil || !info.IsDir() {
+		return true
 	}
 
-	decryptText, err := Decrypt(string(bytes.TrimSpace(data)))
+	err := os.MkdirAll(filepath.Dir(configDir), 0755)
+	if err == nil {
+		err = os.Rename(legacyDir, configDir)
+	}
 	if err != nil {
-		LogError(fmt.Errorf("failed to decrypt token: %w", err))
-		return "", false
+		fmt.Fprintf(os.Stderr, "Warning: failed to move the kncli settings from %s to %s (%v); still using %s.\n", legacyDir, configDir, err, legacyDir)
+		return false
 	}
-
-	return decryptText, true
+	fmt.Fprintf(os.Stderr, "Moved the kncli settings from %s to %s.\n", legacyDir, configDir)
+	return true
 }
 
 // Log Error Function
 
+// LogError prints err and exits. Errors caused by Ctrl-C or SIGTERM exit with
+// EXIT_INTERRUPTED instead of 1.
 func LogError(err error) {
+	if Interrupted(err) {
+		log.Printf("%sinterrupted: %s%s", RED, err.Error(), WHITE)
+		os.Exit(EXIT_INTERRUPTED)
+	}
 	log.Fatalf("%s%s%s", RED, err.Error(), WHITE)
 }
+
+// Interrupted reports whether the command was cancelled by a signal.
+func Interrupted(err error) bool {
+	return Context().Err() != nil || errors.Is(err, tea.ErrInterrupted)
+}
+
+// IsTerminal reports whether f is an interactive terminal.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// ReadStdinSecret reads a password or token piped on stdin, up to the next
+// newline, so several secrets can be given on separate lines.
+func ReadStdinSecret() (string, error) {
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", fmt.Errorf("nothing was given on stdin")
+	}
+	return line, nil
+}
+
+// WriteFileAtomic writes data to a temporary file next to filename and
+// renames it into place, so an interrupted write never leaves a partial file.
+func WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}